@@ -4,9 +4,13 @@ import (
 	"flag"
 	"fmt"
 	"github.com/go-lean/bevaluate/config"
+	"github.com/go-lean/bevaluate/git"
+	"github.com/go-lean/bevaluate/gotest"
+	"github.com/go-lean/bevaluate/info"
 	"github.com/go-lean/bevaluate/operations"
 	"github.com/go-lean/bevaluate/storage"
 	"gopkg.in/yaml.v3"
+	"io"
 	"os"
 	"path/filepath"
 )
@@ -19,10 +23,45 @@ const (
 	exitCodeIOError
 )
 
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+type runFlags struct {
+	changes     *string
+	patch       *string
+	isFile      *bool
+	base        *string
+	head        *string
+	mergeBase   *bool
+	uncommitted *bool
+	untracked   *bool
+}
+
 func main() {
-	runCMD := flag.NewFlagSet("run", flag.ExitOnError)
-	changes := runCMD.String("changes", "", `The changes to be processed in --name-status format: either the path to a file or the actual content. e.g. --changes "changes.txt" --file | --changes "$(git diff master --name-status)"`)
-	isFile := runCMD.Bool("file", false, `Specifies whether the changes lead to an actual file on disk.`)
+	runCMD, run := newRunFlags("run")
+	explainRun := runCMD.Bool("explain", false, `Print why each selected package was selected after writing the outputs.`)
+	explainRunJSON := runCMD.Bool("json", false, `Print the explanations as JSON instead of plain text.`)
+	format := runCMD.String("format", "", `An additional output format, e.g. --format github-matrix writes the results as $GITHUB_OUTPUT entries.`)
+	runShard := runCMD.String("shard", "", `Only retest the given shard of the packages, balanced by their test durations, e.g. --shard 2/5.`)
+
+	explainCMD, explain := newRunFlags("explain")
+	explainJSON := explainCMD.Bool("json", false, `Print the explanations as JSON instead of plain text.`)
+
+	gitLabCMD, gitLab := newRunFlags("gitlab-pipeline")
+	testCMD, test := newRunFlags("test")
+	testShard := testCMD.String("shard", "", `Only test the given shard of the packages, balanced by their test durations, e.g. --shard 2/5.`)
+
+	coverageCMD := flag.NewFlagSet("coverage", flag.ExitOnError)
+	coveragePkg := coverageCMD.String("package", "", `The directory of the test package the cover profile was recorded for, e.g. --package service/api.`)
+	coverageProfile := coverageCMD.String("profile", "", `The path to the output of go test -coverprofile for the test package.`)
+
+	recordCMD := flag.NewFlagSet("record", flag.ExitOnError)
+	events := recordCMD.String("events", "", `The path to the output of go test -json to be recorded in the test history. Read from stdin when omitted.`)
 
 	if len(os.Args) < 2 {
 		fmt.Println("no cmd selected")
@@ -50,16 +89,79 @@ func main() {
 		err = runCMD.Parse(os.Args[2:])
 		exitOnError(err, "could not parse arguments", exitCodeInvalidArgs)
 
-		content := *changes
-		if *isFile {
-			data, errRead := os.ReadFile(*changes)
-			exitOnError(errRead, "could not read changes file", exitCodeIOError)
+		operation, changeInfos := prepareRun(operations.NewEvaluateOperation(store, cfg), run, root)
+		if *explainRun {
+			operation = operation.WithExplanation(os.Stdout, *explainRunJSON)
+		}
+
+		operation = withShard(operation, *runShard)
 
-			content = string(data)
+		switch *format {
+		case "":
+		case operations.FormatGitHubMatrix:
+			out := openGitHubOutput()
+			defer out.Close()
+
+			operation = operation.WithGitHubOutput(out)
+		default:
+			_, _ = fmt.Fprintf(os.Stderr, "unknown format: %q\n", *format)
+			os.Exit(exitCodeInvalidArgs)
 		}
 
-		operation := operations.NewEvaluateOperation(store, cfg)
-		err = operation.Run(root, content)
+		err = operation.Run(root, changeInfos)
+	case "explain":
+		err = explainCMD.Parse(os.Args[2:])
+		exitOnError(err, "could not parse arguments", exitCodeInvalidArgs)
+
+		operation, changeInfos := prepareRun(operations.NewEvaluateOperation(store, cfg), explain, root)
+		operation = operation.WithoutOutputs().WithExplanation(os.Stdout, *explainJSON, explainCMD.Args()...)
+
+		err = operation.Run(root, changeInfos)
+	case "gitlab-pipeline":
+		err = gitLabCMD.Parse(os.Args[2:])
+		exitOnError(err, "could not parse arguments", exitCodeInvalidArgs)
+
+		operation, changeInfos := prepareRun(operations.NewEvaluateOperation(store, cfg), gitLab, root)
+		operation = operation.WithoutOutputs().WithGitLabPipeline()
+
+		err = operation.Run(root, changeInfos)
+	case "test":
+		err = testCMD.Parse(os.Args[2:])
+		exitOnError(err, "could not parse arguments", exitCodeInvalidArgs)
+
+		operation, changeInfos := prepareRun(operations.NewEvaluateOperation(store, cfg), test, root)
+		operation, err = operation.WithTests(gotest.CommandRunner{}, os.Stdout)
+		exitOnError(err, "invalid test config", exitCodeConfig)
+
+		operation = withShard(operation, *testShard)
+
+		err = operation.Run(root, changeInfos)
+	case "coverage":
+		err = coverageCMD.Parse(os.Args[2:])
+		exitOnError(err, "could not parse arguments", exitCodeInvalidArgs)
+
+		if *coveragePkg == "" || *coverageProfile == "" {
+			_, _ = fmt.Fprintln(os.Stderr, "both --package and --profile are required")
+			os.Exit(exitCodeInvalidArgs)
+		}
+
+		profile, errProfile := os.ReadFile(*coverageProfile)
+		exitOnError(errProfile, "could not read cover profile", exitCodeIOError)
+
+		err = operations.NewCoverageOperation(store, cfg).Run(root, filepath.Clean(*coveragePkg), string(profile))
+	case "record":
+		err = recordCMD.Parse(os.Args[2:])
+		exitOnError(err, "could not parse arguments", exitCodeInvalidArgs)
+
+		var data []byte
+		if *events == "" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(*events)
+		}
+		exitOnError(err, "could not read test events", exitCodeIOError)
+
+		err = operations.NewRecordOperation(store, cfg).Run(string(data))
 	case "init":
 		initOperation := operations.NewInitOperation(store)
 		err = initOperation.Run(cfgPath)
@@ -71,6 +173,90 @@ func main() {
 	exitOnError(err, "could not execute: "+cmd, exitCodeGeneralError)
 }
 
+func newRunFlags(name string) (*flag.FlagSet, runFlags) {
+	set := flag.NewFlagSet(name, flag.ExitOnError)
+
+	return set, runFlags{
+		changes:     set.String("changes", "", `The changes to be processed in --name-status format: either the path to a file or the actual content. e.g. --changes "changes.txt" --file | --changes "$(git diff master -M -C --name-status)"`),
+		patch:       set.String("patch", "", `The changes to be processed as a unified diff: either the path to a file or the actual content. e.g. --patch "changes.patch" --file | --patch "$(git diff master -U0)"`),
+		isFile:      set.Bool("file", false, `Specifies whether the changes or the patch lead to an actual file on disk.`),
+		base:        set.String("base", "", `The git revision to compare against, e.g. --base origin/main. Computes the changes using the local git binary instead of --changes.`),
+		head:        set.String("head", git.DefaultHead, `The git revision containing the changes. Only used together with --base.`),
+		mergeBase:   set.Bool("merge-base", false, `Compare against the merge base of --base and --head instead of --base itself.`),
+		uncommitted: set.Bool("uncommitted", false, `Include staged and unstaged changes of the working tree. Requires --head to be HEAD.`),
+		untracked:   set.Bool("untracked", false, `Include untracked files which are not ignored.`),
+	}
+}
+
+func prepareRun(operation operations.EvaluateBuildOperation, flags runFlags, root string) (operations.EvaluateBuildOperation, []info.ChangeInfo) {
+	var changeInfos []info.ChangeInfo
+	var err error
+
+	if *flags.base != "" {
+		client := git.NewClient(git.CommandRunner{Dir: root})
+		gitRange := git.Range{
+			Base:        *flags.base,
+			Head:        *flags.head,
+			MergeBase:   *flags.mergeBase,
+			Uncommitted: *flags.uncommitted,
+			Untracked:   *flags.untracked,
+		}
+
+		changeInfos, err = client.Changes(gitRange)
+		exitOnError(err, "could not compute git changes", exitCodeGeneralError)
+
+		versions, errVersions := client.Versions(gitRange, root)
+		exitOnError(errVersions, "could not resolve git revisions", exitCodeGeneralError)
+
+		operation = operation.WithVersions(versions)
+	} else if *flags.patch != "" {
+		content := readContent(*flags.patch, *flags.isFile)
+		changeInfos, err = info.ParseUnifiedDiff(content)
+		exitOnError(err, "could not parse patch", exitCodeInvalidArgs)
+	} else {
+		content := readContent(*flags.changes, *flags.isFile)
+		changeInfos, err = info.ParseGitChanges(content)
+		exitOnError(err, "could not parse changes", exitCodeInvalidArgs)
+	}
+
+	return operation, changeInfos
+}
+
+func withShard(operation operations.EvaluateBuildOperation, value string) operations.EvaluateBuildOperation {
+	if value == "" {
+		return operation
+	}
+
+	shard, errShard := gotest.ParseShard(value)
+	exitOnError(errShard, "could not parse shard", exitCodeInvalidArgs)
+
+	return operation.WithShard(shard)
+}
+
+// openGitHubOutput opens the file of GITHUB_OUTPUT, falling back to stdout which closing must leave open.
+func openGitHubOutput() io.WriteCloser {
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		return nopWriteCloser{Writer: os.Stdout}
+	}
+
+	file, errOpen := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	exitOnError(errOpen, "could not open github output", exitCodeIOError)
+
+	return file
+}
+
+func readContent(value string, isFile bool) string {
+	if isFile == false {
+		return value
+	}
+
+	data, errRead := os.ReadFile(value)
+	exitOnError(errRead, "could not read changes file", exitCodeIOError)
+
+	return string(data)
+}
+
 func exitOnError(err error, context string, exitCode int) {
 	if err == nil {
 		return
@@ -4,22 +4,85 @@ type (
 	Config struct {
 		Packages    Packages    `yaml:"packages"`
 		Evaluations Evaluations `yaml:"evaluations"`
+		GitLab      GitLab      `yaml:"gitlab"`
+		Test        Test        `yaml:"test"`
 	}
 
 	Packages struct {
 		IgnoredDirs []string `yaml:"ignored_dirs,flow"`
+		Targets     []Target `yaml:"targets"`
+	}
+
+	Target struct {
+		Name   string   `yaml:"name,omitempty"`
+		GOOS   string   `yaml:"goos"`
+		GOARCH string   `yaml:"goarch"`
+		Tags   []string `yaml:"tags,flow"`
+		Cgo    bool     `yaml:"cgo"`
 	}
 
 	Evaluations struct {
-		DeploymentsDir string       `yaml:"deployments_dir"`
-		RetestOut      string       `yaml:"retest_out"`
-		RedeployOut    string       `yaml:"redeploy_out"`
-		SpecialCases   SpecialCases `yaml:"special_cases"`
+		DeploymentsDir      string          `yaml:"deployments_dir"`
+		Deployables         Deployables     `yaml:"deployables"`
+		RetestOut           string          `yaml:"retest_out"`
+		RedeployOut         string          `yaml:"redeploy_out"`
+		PathStyle           string          `yaml:"path_style"`
+		SkipNeutralChanges  bool            `yaml:"skip_neutral_changes"`
+		NeutralOut          string          `yaml:"neutral_out"`
+		ReportOut           string          `yaml:"report_out"`
+		Outputs             []Output        `yaml:"outputs"`
+		SymbolAnalysis      bool            `yaml:"symbol_analysis"`
+		ModuleDiff          bool            `yaml:"module_diff"`
+		CoverageIndex       string          `yaml:"coverage_index"`
+		SelectTestFunctions bool            `yaml:"select_test_functions"`
+		RunOut              string          `yaml:"run_out"`
+		Ownership           []OwnershipRule `yaml:"ownership"`
+		SpecialCases        SpecialCases    `yaml:"special_cases"`
+	}
+
+	Deployables struct {
+		MainPackages bool     `yaml:"main_packages"`
+		Markers      []string `yaml:"markers,flow"`
+		Patterns     []string `yaml:"patterns,flow"`
+		Include      []string `yaml:"include,flow"`
+		Exclude      []string `yaml:"exclude,flow"`
+	}
+
+	Output struct {
+		Path     string `yaml:"path"`
+		Template string `yaml:"template"`
+	}
+
+	OwnershipRule struct {
+		Match  string   `yaml:"match"`
+		Owners []string `yaml:"owners,flow"`
+	}
+
+	GitLab struct {
+		RetestTemplate string `yaml:"retest_template"`
+		DeployTemplate string `yaml:"deploy_template"`
+		PipelineOut    string `yaml:"pipeline_out"`
+	}
+
+	Test struct {
+		Parallelism int      `yaml:"parallelism"`
+		Flags       []string `yaml:"flags,flow"`
+		Timeout     string   `yaml:"timeout"`
+		ResultsOut  string   `yaml:"results_out"`
+		HistoryFile string   `yaml:"history_file"`
 	}
 
 	SpecialCases struct {
-		RetestTriggers    []string `yaml:"retest_triggers,flow"`
-		FullScaleTriggers []string `yaml:"full_scale_triggers,flow"`
+		RetestTriggers    []string        `yaml:"retest_triggers,flow"`
+		FullScaleTriggers []string        `yaml:"full_scale_triggers,flow"`
+		ScopedTriggers    []ScopedTrigger `yaml:"scoped_triggers"`
+	}
+
+	ScopedTrigger struct {
+		Match     string   `yaml:"match"`
+		Retest    []string `yaml:"retest,flow"`
+		Redeploy  []string `yaml:"redeploy,flow"`
+		Propagate bool     `yaml:"propagate"`
 	}
 )
 
@@ -36,9 +99,20 @@ func Default() Config {
 			DeploymentsDir: "cmd/",
 			RetestOut:      "bevaluate/retest.out",
 			RedeployOut:    "bevaluate/redeploy.out",
+			PathStyle:      "dir",
+			NeutralOut:     "bevaluate/neutral.out",
+			RunOut:         "bevaluate/run.out",
 			SpecialCases: SpecialCases{
 				FullScaleTriggers: []string{"go.mod$"},
 			},
 		},
+		GitLab: GitLab{
+			PipelineOut: "bevaluate/pipeline.yml",
+		},
+		Test: Test{
+			Timeout:     "10m",
+			ResultsOut:  "bevaluate/test-results.json",
+			HistoryFile: "bevaluate/test-history.json",
+		},
 	}
 }
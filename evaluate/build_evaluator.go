@@ -4,19 +4,72 @@ import (
 	"errors"
 	"fmt"
 	"github.com/go-lean/bevaluate/info"
+	"github.com/zyedidia/generic/queue"
 	"github.com/zyedidia/generic/stack"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
 type (
 	BuildEvaluator struct {
-		config Config
+		config     Config
+		classifier ChangeClassifier
+		analyzer   ImpactAnalyzer
+		differ     ModuleDiffer
+		coverage   CoverageSelector
+		tests      TestSelector
+	}
+
+	ChangeClassifier interface {
+		IsNeutral(change info.ChangeInfo) (bool, error)
+	}
+
+	ImpactAnalyzer interface {
+		ChangedSymbols(change info.ChangeInfo) (info.Symbols, bool)
+		Impact(dependant, dependency string, symbols info.Symbols) (info.SymbolImpact, bool)
+	}
+
+	ModuleDiffer interface {
+		Diff(change info.ChangeInfo) (info.ModuleChanges, error)
+	}
+
+	CoverageSelector interface {
+		TestsCovering(change info.ChangeInfo) ([]string, bool)
+	}
+
+	TestSelector interface {
+		ChangedTests(change info.ChangeInfo) ([]string, bool)
+	}
+
+	symbolChange struct {
+		node    *DependencyNode
+		symbols info.Symbols
+		reason  Reason
+	}
+
+	reasonedNode struct {
+		node   *DependencyNode
+		reason Reason
 	}
 
 	Evaluation struct {
-		Retest   []string
-		Redeploy []string
+		Retest       []string
+		Redeploy     []string
+		Neutral      []string
+		Reasons      map[string]Reason
+		SpecialCases []Reason
+		FullScale    bool
+		Testable     []string
+		Deployable   []string
+		// Packages holds every evaluated package, Excluded the ones without files of the target.
+		Packages []string
+		Excluded []string
+		// TestFunctions holds the only test functions to run of the retested packages,
+		// the whole package is retested when it is missing.
+		TestFunctions map[string][]string
+		// Deployments holds the manifests of the redeployed packages declaring one.
+		Deployments map[string]info.DeploymentManifest
 	}
 )
 
@@ -34,22 +87,62 @@ func NewBuildEvaluator(config Config) BuildEvaluator {
 	return BuildEvaluator{config: config}
 }
 
+func (e BuildEvaluator) WithClassifier(classifier ChangeClassifier) BuildEvaluator {
+	e.classifier = classifier
+	return e
+}
+
+func (e BuildEvaluator) WithAnalyzer(analyzer ImpactAnalyzer) BuildEvaluator {
+	e.analyzer = analyzer
+	return e
+}
+
+func (e BuildEvaluator) WithModuleDiffer(differ ModuleDiffer) BuildEvaluator {
+	e.differ = differ
+	return e
+}
+
+func (e BuildEvaluator) WithCoverage(coverage CoverageSelector) BuildEvaluator {
+	e.coverage = coverage
+	return e
+}
+
+func (e BuildEvaluator) WithTestSelector(tests TestSelector) BuildEvaluator {
+	e.tests = tests
+	return e
+}
+
 func (e BuildEvaluator) Evaluate(packages []info.PackageInfo, changes []info.ChangeInfo) (Evaluation, error) {
 	graph := NewDependencyGraph(packages)
 	if errBuild := graph.Build(); errBuild != nil {
 		return Evaluation{}, fmt.Errorf("could not build dependency graph: %w", errBuild)
 	}
 
+	if errOwnership := e.validateOwnership(graph); errOwnership != nil {
+		return Evaluation{}, fmt.Errorf("invalid ownership config: %w", errOwnership)
+	}
+
 	issuedFullRetest := false
-	for _, change := range changes {
+	fullScale := false
+	neutral := make([]string, 0)
+	specialCases := make([]Reason, 0)
+	for _, change := range expandChanges(changes) {
+		if e.isNeutral(change) {
+			neutral = append(neutral, change.Path)
+			continue
+		}
+
 		errEvaluate := e.evaluateChange(change, graph)
 		if errEvaluate == nil {
 			continue
 		}
 
 		if errors.Is(errEvaluate, ErrSpecialRetestCase) {
+			reason := specialCaseReason(change, errEvaluate)
+			specialCases = append(specialCases, reason)
+
 			if issuedFullRetest == false {
-				issueFullScaleRetest(graph)
+				issueFullScaleRetest(graph, reason)
 				issuedFullRetest = true
 			}
 
@@ -58,8 +151,11 @@ func (e BuildEvaluator) Evaluate(packages []info.PackageInfo, changes []info.Cha
 
 		if errors.Is(errEvaluate, ErrUnsupportedScenario) ||
 			errors.Is(errEvaluate, ErrSpecialFullScaleCase) {
-			issueFullScaleRetest(graph)
-			e.issueFullScaleRedeploy(graph)
+			reason := specialCaseReason(change, errEvaluate)
+			specialCases = append(specialCases, reason)
+			issueFullScaleRetest(graph, reason)
+			e.issueFullScaleRedeploy(graph, reason)
+			fullScale = true
 
 			break
 		}
@@ -67,15 +163,70 @@ func (e BuildEvaluator) Evaluate(packages []info.PackageInfo, changes []info.Cha
 		return Evaluation{}, fmt.Errorf("could not evaluate change: %w", errEvaluate)
 	}
 
-	result := prepareEvaluation(graph)
+	result := e.prepareEvaluation(graph)
+	result.Neutral = neutral
+	result.SpecialCases = specialCases
+	result.FullScale = fullScale
 	return result, nil
 }
 
+func (e Evaluation) Merge(other Evaluation) Evaluation {
+	return Evaluation{
+		Retest:        mergeUnique(e.Retest, other.Retest),
+		Redeploy:      mergeUnique(e.Redeploy, other.Redeploy),
+		Neutral:       mergeUnique(e.Neutral, other.Neutral),
+		Reasons:       mergeReasons(e.Reasons, other.Reasons),
+		SpecialCases:  mergeSpecialCases(e.SpecialCases, other.SpecialCases),
+		FullScale:     e.FullScale || other.FullScale,
+		Testable:      mergeUnique(e.Testable, other.Testable),
+		Deployable:    mergeUnique(e.Deployable, other.Deployable),
+		Packages:      mergeUnique(e.Packages, other.Packages),
+		Excluded:      mergeUnique(e.Excluded, other.Excluded),
+		TestFunctions: mergeTestFunctions(e, other),
+		Deployments:   mergeDeployments(e.Deployments, other.Deployments),
+	}
+}
+
+func (e BuildEvaluator) isNeutral(change info.ChangeInfo) bool {
+	if e.classifier == nil {
+		return false
+	}
+
+	neutral, errClassify := e.classifier.IsNeutral(change)
+	if errClassify != nil {
+		return false // could not be classified, so the change is evaluated as usual
+	}
+
+	return neutral
+}
+
 func (e BuildEvaluator) evaluateChange(change info.ChangeInfo, graph DependencyGraph) error {
+	if e.differ != nil && info.IsModuleFile(change.Path) {
+		moduleChanges, errDiff := e.differ.Diff(change)
+		if errDiff == nil {
+			return e.markModuleDependants(change.Path, moduleChanges, graph)
+		}
+	}
+
 	if errSpecialCase := e.evaluateSpecialCase(change); errSpecialCase != nil {
 		return errSpecialCase
 	}
 
+	if info.IsWorkspaceFile(change.Path) {
+		return specialCaseError{err: ErrSpecialFullScaleCase, trigger: "workspace file changed"}
+	}
+
+	// scoped triggers add to the evaluation, a go file still affects its own package and dependants
+	triggered := e.markScopedTriggers(change.Path, graph)
+	declared := e.markDeploymentInputs(change.Path, graph)
+	if strings.HasSuffix(change.Path, ".go") == false {
+		owned := e.markOwners(change.Path, graph)
+		embedded := e.markEmbeddingPackages(change.Path, graph)
+		if owned || embedded || declared || triggered {
+			return nil
+		}
+	}
+
 	pkgPath := filepath.Dir(change.Path)
 	if pkgPath == "." {
 		return nil // unhandled special case, should be added in config
@@ -86,20 +237,39 @@ func (e BuildEvaluator) evaluateChange(change info.ChangeInfo, graph DependencyG
 		return e.handleMissingPackage(pkgPath, change, graph)
 	}
 
+	if pkg.IsExcluded() || pkg.IsIgnoredFile(change.Path) {
+		return nil // not part of the evaluated target
+	}
+
+	origin := Reason{Change: change.Path}
 	if strings.HasSuffix(change.Path, "_test.go") {
 		if pkg.ContainsTests {
-			pkg.retest = true
+			e.markChangedTests(pkg, change, origin.via(pkg.Path))
 		}
 		return nil
 	}
 
-	e.markPackageDirtyRecursively(pkg)
+	if e.coverage != nil {
+		if tests, ok := e.coverage.TestsCovering(change); ok && markCoveringTests(tests, origin, graph) {
+			e.markPackageRedeployRecursively(pkg, origin)
+			return nil
+		}
+	}
+
+	if e.analyzer != nil {
+		if symbols, ok := e.analyzer.ChangedSymbols(change); ok {
+			e.markPackageDirtyBySymbols(pkg, symbols, origin)
+			return nil
+		}
+	}
+
+	e.markPackageDirtyRecursively(pkg, origin)
 	return nil
 }
 
 func (e BuildEvaluator) handleMissingPackage(pkgPath string, change info.ChangeInfo, graph DependencyGraph) error {
 	if strings.HasSuffix(change.Path, ".go") {
-		if change.IsDeleted {
+		if change.IsDeleted() {
 			return nil
 		}
 
@@ -108,20 +278,184 @@ func (e BuildEvaluator) handleMissingPackage(pkgPath string, change info.ChangeI
 
 	parent, ok := findParentRecursively(pkgPath, graph)
 	if ok == false {
-		return ErrUnsupportedScenario
+		return specialCaseError{err: ErrUnsupportedScenario, trigger: "no parent package"}
+	}
+
+	e.markPackageDirtyRecursively(parent, Reason{Change: change.Path})
+	return nil
+}
+
+func expandChanges(changes []info.ChangeInfo) []info.ChangeInfo {
+	result := make([]info.ChangeInfo, 0, len(changes))
+
+	for _, change := range changes {
+		switch change.Status {
+		case info.StatusRenamed:
+			result = append(result,
+				info.ChangeInfo{Status: info.StatusDeleted, Path: change.OldPath},
+				info.ChangeInfo{Status: info.StatusModified, Path: change.Path})
+		case info.StatusCopied:
+			result = append(result, info.ChangeInfo{Status: info.StatusAdded, Path: change.Path})
+		default:
+			result = append(result, change)
+		}
+	}
+
+	return result
+}
+
+func (e BuildEvaluator) markModuleDependants(path string, changes info.ModuleChanges, graph DependencyGraph) error {
+	if changes.FullScale {
+		return specialCaseError{err: ErrSpecialFullScaleCase, trigger: "go or toolchain directive changed"}
+	}
+
+	moduleDir := filepath.Dir(path)
+	workspace := false
+	for _, node := range graph.Nodes {
+		if node.Module.Dir == moduleDir {
+			workspace = node.Module.Workspace
+			break
+		}
+	}
+
+	for _, node := range graph.Nodes {
+		// independent modules resolve their external dependencies through their own go.mod, while
+		// the modules of a workspace share one build list selected across all of their go.mod files
+		if node.Module.Dir != moduleDir && (workspace == false || node.Module.Workspace == false) {
+			continue
+		}
+
+		for _, dependency := range node.ExternalDependencies {
+			if changes.Affects(dependency) {
+				e.markPackageDirtyRecursively(node, Reason{Change: path})
+				break
+			}
+		}
 	}
 
-	e.markPackageDirtyRecursively(parent)
 	return nil
 }
 
-func (e BuildEvaluator) markPackageDirtyRecursively(pkg *DependencyNode) {
-	pkgStack := stack.New[*DependencyNode]()
-	pkgStack.Push(pkg)
+func (e BuildEvaluator) validateOwnership(graph DependencyGraph) error {
+	for _, rule := range e.config.Ownership {
+		for _, owner := range rule.Owners {
+			if _, ok := graph.NodesMap[owner]; ok == false {
+				return fmt.Errorf("could not find owner %q of %q", owner, rule.Match.String())
+			}
+		}
+	}
+
+	return nil
+}
+
+func (e BuildEvaluator) markOwners(path string, graph DependencyGraph) bool {
+	owned := false
+
+	for _, rule := range e.config.Ownership {
+		if rule.Match.MatchString(path) == false {
+			continue
+		}
+
+		owned = true
+		for _, owner := range rule.Owners {
+			node := graph.NodesMap[owner]
+			if node.IsExcluded() {
+				continue // not part of the evaluated target
+			}
+
+			e.markPackageDirtyRecursively(node, Reason{Change: path})
+		}
+	}
+
+	return owned
+}
+
+// markDeploymentInputs marks the deployments declaring the path as an input in their manifest,
+// without affecting their dependencies or tests.
+func (e BuildEvaluator) markDeploymentInputs(path string, graph DependencyGraph) bool {
+	declared := false
+
+	for _, node := range graph.Nodes {
+		if node.IsExcluded() || e.canBeDeployed(node) == false || node.IsDeploymentInput(path) == false {
+			continue
+		}
+
+		declared = true
+		node.markRedeploy(Reason{Change: path}.via(node.Path))
+	}
+
+	return declared
+}
+
+func (e BuildEvaluator) markScopedTriggers(path string, graph DependencyGraph) bool {
+	triggered := false
+
+	for _, trigger := range e.config.SpecialCases.ScopedTriggers {
+		if trigger.Match.MatchString(path) == false {
+			continue
+		}
+
+		triggered = true
+		origin := Reason{Change: path, Trigger: "scoped trigger " + trigger.Match.String()}
+
+		for _, node := range graph.Nodes {
+			if node.IsExcluded() {
+				continue // not part of the evaluated target
+			}
+
+			if matchesPackage(trigger.Retest, node.Path) {
+				if trigger.Propagate {
+					markRetestRecursively(node, origin)
+				} else if node.ContainsTests {
+					node.markRetest(origin.via(node.Path))
+				}
+			}
+
+			if matchesPackage(trigger.Redeploy, node.Path) {
+				if trigger.Propagate {
+					e.markPackageRedeployRecursively(node, origin)
+				} else if e.canBeDeployed(node) {
+					node.markRedeploy(origin.via(node.Path))
+				}
+			}
+		}
+	}
+
+	return triggered
+}
+
+func (e BuildEvaluator) markEmbeddingPackages(path string, graph DependencyGraph) bool {
+	embedded := false
+
+	for _, node := range graph.Nodes {
+		isEmbedded, testOnly := node.EmbedsFile(path)
+		if isEmbedded == false {
+			continue
+		}
+
+		embedded = true
+		origin := Reason{Change: path}
+		if testOnly {
+			if node.ContainsTests {
+				node.markRetest(origin.via(node.Path))
+			}
+			continue
+		}
+
+		e.markPackageDirtyRecursively(node, origin)
+	}
+
+	return embedded
+}
+
+func (e BuildEvaluator) markPackageDirtyRecursively(pkg *DependencyNode, origin Reason) {
+	pkgStack := stack.New[reasonedNode]()
+	pkgStack.Push(reasonedNode{node: pkg, reason: origin.via(pkg.Path)})
 	visited := make(map[string]struct{}, defaultDependencyLevels)
 
 	for pkgStack.Size() > 0 {
-		p := pkgStack.Pop()
+		item := pkgStack.Pop()
+		p := item.node
 
 		if _, ok := visited[p.Path]; ok {
 			continue
@@ -130,15 +464,173 @@ func (e BuildEvaluator) markPackageDirtyRecursively(pkg *DependencyNode) {
 		visited[p.Path] = struct{}{}
 
 		if p.ContainsTests {
-			p.retest = true
+			p.markRetest(item.reason)
 		}
 
 		if e.canBeDeployed(p) {
-			p.redeploy = true
+			p.markRedeploy(item.reason)
 		}
 
 		for _, dependant := range p.Dependants {
-			pkgStack.Push(dependant)
+			pkgStack.Push(reasonedNode{node: dependant, reason: item.reason.via(dependant.Path)})
+		}
+
+		for _, dependant := range p.TestDependants {
+			if dependant.ContainsTests {
+				dependant.markRetest(item.reason.via(dependant.Path)) // only the tests of a test dependant are built with the change
+			}
+		}
+	}
+}
+
+func markRetestRecursively(pkg *DependencyNode, origin Reason) {
+	pkgStack := stack.New[reasonedNode]()
+	pkgStack.Push(reasonedNode{node: pkg, reason: origin.via(pkg.Path)})
+	visited := make(map[string]struct{}, defaultDependencyLevels)
+
+	for pkgStack.Size() > 0 {
+		item := pkgStack.Pop()
+		p := item.node
+
+		if _, ok := visited[p.Path]; ok {
+			continue
+		}
+
+		visited[p.Path] = struct{}{}
+
+		if p.ContainsTests {
+			p.markRetest(item.reason)
+		}
+
+		for _, dependant := range p.Dependants {
+			pkgStack.Push(reasonedNode{node: dependant, reason: item.reason.via(dependant.Path)})
+		}
+
+		for _, dependant := range p.TestDependants {
+			if dependant.ContainsTests {
+				dependant.markRetest(item.reason.via(dependant.Path))
+			}
+		}
+	}
+}
+
+func (e BuildEvaluator) markPackageRedeployRecursively(pkg *DependencyNode, origin Reason) {
+	pkgStack := stack.New[reasonedNode]()
+	pkgStack.Push(reasonedNode{node: pkg, reason: origin.via(pkg.Path)})
+	visited := make(map[string]struct{}, defaultDependencyLevels)
+
+	for pkgStack.Size() > 0 {
+		item := pkgStack.Pop()
+		p := item.node
+
+		if _, ok := visited[p.Path]; ok {
+			continue
+		}
+
+		visited[p.Path] = struct{}{}
+
+		if e.canBeDeployed(p) {
+			p.markRedeploy(item.reason)
+		}
+
+		for _, dependant := range p.Dependants {
+			pkgStack.Push(reasonedNode{node: dependant, reason: item.reason.via(dependant.Path)})
+		}
+	}
+}
+
+func (e BuildEvaluator) markChangedTests(pkg *DependencyNode, change info.ChangeInfo, reason Reason) {
+	if e.tests != nil {
+		if functions, ok := e.tests.ChangedTests(change); ok {
+			pkg.markRetestFunctions(reason, functions)
+			return
+		}
+	}
+
+	pkg.markRetest(reason)
+}
+
+func markCoveringTests(tests []string, origin Reason, graph DependencyGraph) bool {
+	marked := false
+
+	for _, test := range tests {
+		node, ok := graph.NodesMap[test]
+		if ok == false || node.ContainsTests == false || node.IsExcluded() {
+			continue // the coverage was recorded for a package which does not exist anymore
+		}
+
+		node.markRetest(Reason{Change: origin.Change, Trigger: "covered by the tests"})
+		marked = true
+	}
+
+	return marked
+}
+
+func (e BuildEvaluator) markPackageDirtyBySymbols(pkg *DependencyNode, symbols info.Symbols, origin Reason) {
+	changesQueue := queue.New[symbolChange]()
+	changesQueue.Enqueue(symbolChange{node: pkg, symbols: symbols, reason: origin.via(pkg.Path)})
+	processed := make(map[string]info.Symbols, defaultDependencyLevels)
+
+	for changesQueue.Empty() == false {
+		change := changesQueue.Dequeue()
+		p := change.node
+
+		if p.ContainsTests {
+			p.markRetest(change.reason)
+		}
+
+		if e.canBeDeployed(p) {
+			p.markRedeploy(change.reason)
+		}
+
+		if len(change.symbols) == 0 {
+			continue
+		}
+
+		for _, dependant := range p.TestDependants {
+			impact, ok := e.analyzer.Impact(dependant.Path, p.Path, change.symbols)
+			if (ok == false || impact.TestReferenced) && dependant.ContainsTests {
+				dependant.markRetest(change.reason.via(dependant.Path))
+			}
+		}
+
+		for _, dependant := range p.Dependants {
+			impact, ok := e.analyzer.Impact(dependant.Path, p.Path, change.symbols)
+			if ok == false {
+				e.markPackageDirtyRecursively(dependant, change.reason)
+				continue
+			}
+
+			if impact.TestReferenced && dependant.ContainsTests {
+				dependant.markRetest(change.reason.via(dependant.Path))
+			}
+
+			if impact.Referenced == false {
+				continue
+			}
+
+			unprocessed := make(info.Symbols, len(impact.Symbols))
+			for symbol := range impact.Symbols {
+				if _, ok := processed[dependant.Path][symbol]; ok {
+					continue
+				}
+
+				unprocessed[symbol] = struct{}{}
+			}
+
+			if _, visited := processed[dependant.Path]; visited && len(unprocessed) == 0 {
+				continue
+			}
+
+			if processed[dependant.Path] == nil {
+				processed[dependant.Path] = make(info.Symbols, len(unprocessed))
+			}
+
+			for symbol := range unprocessed {
+				processed[dependant.Path][symbol] = struct{}{}
+			}
+
+			changesQueue.Enqueue(symbolChange{node: dependant, symbols: unprocessed, reason: change.reason.via(dependant.Path)})
 		}
 	}
 }
@@ -159,48 +651,81 @@ func findParentRecursively(pkgPath string, graph DependencyGraph) (*DependencyNo
 	return nil, false
 }
 
-func prepareEvaluation(graph DependencyGraph) Evaluation {
+func (e BuildEvaluator) prepareEvaluation(graph DependencyGraph) Evaluation {
 	l := len(graph.Nodes)
 	retest := make([]string, 0, l)
 	redeploy := make([]string, 0, l)
+	reasons := make(map[string]Reason, l)
+	testable := make([]string, 0, l)
+	deployable := make([]string, 0, l)
+	packages := make([]string, 0, l)
+	excluded := make([]string, 0)
+	testFunctions := make(map[string][]string)
+	deployments := make(map[string]info.DeploymentManifest)
 
 	for _, node := range graph.Nodes {
+		packages = append(packages, node.Path)
+		if node.IsExcluded() {
+			excluded = append(excluded, node.Path)
+		}
+		if node.ContainsTests && node.IsExcluded() == false {
+			testable = append(testable, node.Path)
+		}
+		if e.canBeDeployed(node) && node.IsExcluded() == false {
+			deployable = append(deployable, node.Path)
+		}
 		if node.retest {
 			retest = append(retest, node.Path)
 		}
 		if node.redeploy {
 			redeploy = append(redeploy, node.Path)
 		}
+		if node.redeploy && node.Manifest != nil {
+			deployments[node.Path] = *node.Manifest
+		}
+		if functions := node.retestFunctions(); functions != nil {
+			testFunctions[node.Path] = functions
+		}
+		if (node.retest || node.redeploy) && node.reason != nil {
+			reasons[node.Path] = *node.reason
+		}
 	}
 
 	return Evaluation{
-		Retest:   retest,
-		Redeploy: redeploy,
+		Retest:        retest,
+		Redeploy:      redeploy,
+		Reasons:       reasons,
+		Testable:      testable,
+		Deployable:    deployable,
+		Packages:      packages,
+		Excluded:      excluded,
+		TestFunctions: testFunctions,
+		Deployments:   deployments,
 	}
 }
 
-func issueFullScaleRetest(g DependencyGraph) {
+func issueFullScaleRetest(g DependencyGraph, reason Reason) {
 	for _, node := range g.Nodes {
-		if node.ContainsTests == false {
+		if node.ContainsTests == false || node.IsExcluded() {
 			continue
 		}
 
-		node.retest = true
+		node.markRetest(reason)
 	}
 }
 
-func (e BuildEvaluator) issueFullScaleRedeploy(g DependencyGraph) {
+func (e BuildEvaluator) issueFullScaleRedeploy(g DependencyGraph, reason Reason) {
 	for _, node := range g.Nodes {
-		if e.canBeDeployed(node) == false {
+		if e.canBeDeployed(node) == false || node.IsExcluded() {
 			continue
 		}
 
-		node.redeploy = true
+		node.markRedeploy(reason)
 	}
 }
 
 func (e BuildEvaluator) canBeDeployed(node *DependencyNode) bool {
-	return strings.HasPrefix(node.Path, e.config.DeploymentsDir)
+	return e.config.IsDeployable(node.PackageInfo)
 }
 
 func (e BuildEvaluator) evaluateSpecialCase(change info.ChangeInfo) error {
@@ -209,7 +734,7 @@ func (e BuildEvaluator) evaluateSpecialCase(change info.ChangeInfo) error {
 			continue
 		}
 
-		return ErrSpecialRetestCase
+		return specialCaseError{err: ErrSpecialRetestCase, trigger: "retest trigger " + trigger.String()}
 	}
 
 	for _, trigger := range e.config.SpecialCases.FullScaleTriggers {
@@ -217,8 +742,98 @@ func (e BuildEvaluator) evaluateSpecialCase(change info.ChangeInfo) error {
 			continue
 		}
 
-		return ErrSpecialFullScaleCase
+		return specialCaseError{err: ErrSpecialFullScaleCase, trigger: "full scale trigger " + trigger.String()}
 	}
 
 	return nil
 }
+
+func specialCaseReason(change info.ChangeInfo, err error) Reason {
+	var special specialCaseError
+	if errors.As(err, &special) {
+		return Reason{Change: change.Path, Trigger: special.trigger}
+	}
+
+	return Reason{Change: change.Path, Trigger: err.Error()}
+}
+
+func mergeUnique(first, second []string) []string {
+	result := make([]string, 0, len(first)+len(second))
+	seen := make(map[string]struct{}, len(first)+len(second))
+
+	for _, items := range [][]string{first, second} {
+		for _, item := range items {
+			if _, ok := seen[item]; ok {
+				continue
+			}
+
+			seen[item] = struct{}{}
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+func mergeReasons(first, second map[string]Reason) map[string]Reason {
+	result := make(map[string]Reason, len(first)+len(second))
+
+	for _, reasons := range []map[string]Reason{second, first} {
+		for pkg, reason := range reasons {
+			result[pkg] = reason // the reasons of the first evaluation win
+		}
+	}
+
+	return result
+}
+
+func mergeSpecialCases(first, second []Reason) []Reason {
+	seen := make(map[string]struct{}, len(first)+len(second))
+	result := make([]Reason, 0, len(first)+len(second))
+
+	for _, reasons := range [][]Reason{first, second} {
+		for _, reason := range reasons {
+			if _, ok := seen[reason.String()]; ok {
+				continue
+			}
+
+			seen[reason.String()] = struct{}{}
+			result = append(result, reason)
+		}
+	}
+
+	return result
+}
+
+// mergeTestFunctions keeps the test functions of a package only when no evaluation retests it as a whole.
+func mergeTestFunctions(first, second Evaluation) map[string][]string {
+	result := make(map[string][]string, len(first.TestFunctions)+len(second.TestFunctions))
+
+	for _, evaluations := range [][2]Evaluation{{first, second}, {second, first}} {
+		current, other := evaluations[0], evaluations[1]
+
+		for pkg, functions := range current.TestFunctions {
+			otherFunctions, ok := other.TestFunctions[pkg]
+			if ok == false && contains(other.Retest, pkg) {
+				continue
+			}
+
+			result[pkg] = mergeUnique(functions, otherFunctions)
+			sort.Strings(result[pkg])
+		}
+	}
+
+	return result
+}
+
+func mergeDeployments(first, second map[string]info.DeploymentManifest) map[string]info.DeploymentManifest {
+	result := make(map[string]info.DeploymentManifest, len(first)+len(second))
+
+	for _, deployments := range []map[string]info.DeploymentManifest{second, first} {
+		for pkg, manifest := range deployments {
+			result[pkg] = manifest // the manifests of the first evaluation win
+		}
+	}
+
+	return result
+}
@@ -1,6 +1,7 @@
 package evaluate_test
 
 import (
+	"errors"
 	"github.com/go-lean/bevaluate/evaluate"
 	"github.com/go-lean/bevaluate/info"
 	"github.com/stretchr/testify/require"
@@ -73,8 +74,8 @@ func TestBuildEvaluator_Evaluate_DeletedSourceFileMissingPackage_NoError(t *test
 			Path: "other/server.go",
 		},
 		{
-			Path:      "baba/server.go",
-			IsDeleted: true,
+			Path:   "baba/server.go",
+			Status: info.StatusDeleted,
 		},
 	}
 
@@ -432,3 +433,1075 @@ func TestBuildEvaluator_Evaluate_SharedDependencies_ShouldPropagateCorrectly(t *
 	expectedRedeploy := []string{"cmd/baba", "cmd/other"}
 	require.ElementsMatch(t, expectedRedeploy, result.Redeploy)
 }
+
+func TestBuildEvaluator_Evaluate_RenamedBetweenPackages_DirtiesBothSides(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/baba",
+			Dependencies: []string{"baba"},
+		},
+		{
+			Path:         "cmd/other",
+			Dependencies: []string{"other"},
+		},
+		{
+			Path:          "baba",
+			ContainsTests: true,
+		},
+		{
+			Path:          "other",
+			ContainsTests: true,
+		},
+		{
+			Path:          "common",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Status:  info.StatusRenamed,
+			OldPath: "baba/server.go",
+			Path:    "other/server.go",
+		},
+	}
+
+	eval := evaluate.NewBuildEvaluator(testCfg())
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	expectedRetest := []string{"baba", "other"}
+	require.ElementsMatch(t, expectedRetest, result.Retest)
+
+	expectedRedeploy := []string{"cmd/baba", "cmd/other"}
+	require.ElementsMatch(t, expectedRedeploy, result.Redeploy)
+}
+
+func TestBuildEvaluator_Evaluate_RenamedFromRemovedPackage_OnlyDirtiesNewSide(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/other",
+			Dependencies: []string{"other"},
+		},
+		{
+			Path:          "other",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Status:  info.StatusRenamed,
+			OldPath: "baba/server.go",
+			Path:    "other/server.go",
+		},
+	}
+
+	eval := evaluate.NewBuildEvaluator(testCfg())
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+	require.Equal(t, []string{"other"}, result.Retest)
+	require.Equal(t, []string{"cmd/other"}, result.Redeploy)
+}
+
+func TestBuildEvaluator_Evaluate_Copied_OnlyDirtiesNewSide(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:          "baba",
+			ContainsTests: true,
+		},
+		{
+			Path:          "other",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Status:  info.StatusCopied,
+			OldPath: "baba/server.go",
+			Path:    "other/server.go",
+		},
+	}
+
+	eval := evaluate.NewBuildEvaluator(testCfg())
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+	require.Equal(t, []string{"other"}, result.Retest)
+	require.Empty(t, result.Redeploy)
+}
+
+type (
+	FakeClassifier struct {
+		neutral map[string]bool
+	}
+)
+
+func (c FakeClassifier) IsNeutral(change info.ChangeInfo) (bool, error) {
+	neutral, ok := c.neutral[change.Path]
+	if ok == false {
+		return false, errors.New("kaboom")
+	}
+
+	return neutral, nil
+}
+
+func TestBuildEvaluator_Evaluate_NeutralChanges_AreSkipped(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/baba",
+			Dependencies: []string{"baba"},
+		},
+		{
+			Path:         "cmd/other",
+			Dependencies: []string{"other"},
+		},
+		{
+			Path:          "baba",
+			ContainsTests: true,
+		},
+		{
+			Path:          "other",
+			ContainsTests: true,
+		},
+		{
+			Path:          "common",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Status: info.StatusModified,
+			Path:   "baba/server.go",
+		},
+		{
+			Status: info.StatusModified,
+			Path:   "other/server.go",
+		},
+		{
+			Status: info.StatusModified,
+			Path:   "common/errors.go",
+		},
+	}
+
+	classifier := FakeClassifier{neutral: map[string]bool{"baba/server.go": true, "other/server.go": false}}
+	eval := evaluate.NewBuildEvaluator(testCfg()).WithClassifier(classifier)
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.ElementsMatch(t, []string{"other", "common"}, result.Retest)
+	require.Equal(t, []string{"cmd/other"}, result.Redeploy)
+	require.Equal(t, []string{"baba/server.go"}, result.Neutral)
+}
+
+type (
+	FakeAnalyzer struct {
+		changed map[string]info.Symbols
+		impacts map[string]info.SymbolImpact
+	}
+)
+
+func (a FakeAnalyzer) ChangedSymbols(change info.ChangeInfo) (info.Symbols, bool) {
+	symbols, ok := a.changed[change.Path]
+	return symbols, ok
+}
+
+func (a FakeAnalyzer) Impact(dependant, dependency string, _ info.Symbols) (info.SymbolImpact, bool) {
+	impact, ok := a.impacts[dependency+"->"+dependant]
+	return impact, ok
+}
+
+func TestBuildEvaluator_Evaluate_SymbolAnalysis_OnlyReferencingDependants(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/baba",
+			Dependencies: []string{"baba"},
+		},
+		{
+			Path:         "cmd/other",
+			Dependencies: []string{"other"},
+		},
+		{
+			Path:         "cmd/flag",
+			Dependencies: []string{"flag"},
+		},
+		{
+			Path:          "baba",
+			ContainsTests: true,
+			Dependencies:  []string{"util"},
+		},
+		{
+			Path:          "other",
+			ContainsTests: true,
+			Dependencies:  []string{"util"},
+		},
+		{
+			Path:          "flag",
+			ContainsTests: true,
+			Dependencies:  []string{"util"},
+		},
+		{
+			Path:          "util",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Status: info.StatusModified,
+			Path:   "util/strings.go",
+		},
+	}
+
+	analyzer := FakeAnalyzer{
+		changed: map[string]info.Symbols{"util/strings.go": {"Join": {}}},
+		impacts: map[string]info.SymbolImpact{
+			"util->baba":       {Referenced: true, Symbols: info.Symbols{"Greet": {}}},
+			"util->other":      {TestReferenced: true, Symbols: info.Symbols{}},
+			"baba->cmd/baba":   {Referenced: true, Symbols: info.Symbols{}},
+			"other->cmd/other": {Referenced: true, Symbols: info.Symbols{}},
+		},
+	}
+	eval := evaluate.NewBuildEvaluator(testCfg()).WithAnalyzer(analyzer)
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.ElementsMatch(t, []string{"util", "baba", "other", "flag"}, result.Retest)
+	require.ElementsMatch(t, []string{"cmd/baba", "cmd/flag"}, result.Redeploy)
+}
+
+func TestBuildEvaluator_Evaluate_SymbolAnalysisNotPossible_FallsBackToPackage(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/baba",
+			Dependencies: []string{"baba"},
+		},
+		{
+			Path:          "baba",
+			ContainsTests: true,
+			Dependencies:  []string{"util"},
+		},
+		{
+			Path:          "util",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Status: info.StatusModified,
+			Path:   "util/strings.go",
+		},
+	}
+
+	eval := evaluate.NewBuildEvaluator(testCfg()).WithAnalyzer(FakeAnalyzer{})
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.ElementsMatch(t, []string{"util", "baba"}, result.Retest)
+	require.ElementsMatch(t, []string{"cmd/baba"}, result.Redeploy)
+}
+
+type (
+	FakeModuleDiffer struct {
+		changes info.ModuleChanges
+	}
+)
+
+func (d FakeModuleDiffer) Diff(_ info.ChangeInfo) (info.ModuleChanges, error) {
+	return d.changes, nil
+}
+
+func moduleDiffPackages(workspace bool) []info.PackageInfo {
+	rootModule := info.Module{Path: "github.com/baba/is/you", Dir: ".", Workspace: workspace}
+	toolsModule := info.Module{Path: "github.com/baba/is/you/tools", Dir: "tools", Workspace: workspace}
+
+	return []info.PackageInfo{
+		{
+			Path:         "cmd/baba",
+			Module:       rootModule,
+			Dependencies: []string{"baba"},
+		},
+		{
+			Path:         "cmd/other",
+			Module:       rootModule,
+			Dependencies: []string{"other"},
+		},
+		{
+			Path:                 "baba",
+			Module:               rootModule,
+			ContainsTests:        true,
+			ExternalDependencies: []string{"gopkg.in/yaml.v3"},
+		},
+		{
+			Path:                 "other",
+			Module:               rootModule,
+			ContainsTests:        true,
+			ExternalDependencies: []string{"github.com/stretchr/testify/require"},
+		},
+		{
+			Path:                 "tools/lint",
+			Module:               toolsModule,
+			ContainsTests:        true,
+			ExternalDependencies: []string{"gopkg.in/yaml.v3"},
+		},
+	}
+}
+
+func TestBuildEvaluator_Evaluate_ModuleDiff_OnlyImportingPackages(t *testing.T) {
+	packages := moduleDiffPackages(false)
+	changes := []info.ChangeInfo{
+		{
+			Status: info.StatusModified,
+			Path:   "go.mod",
+		},
+	}
+
+	differ := FakeModuleDiffer{changes: info.ModuleChanges{
+		Modules:  []string{"gopkg.in/yaml.v3"},
+		Required: []string{"gopkg.in/yaml.v3", "github.com/stretchr/testify"},
+	}}
+	eval := evaluate.NewBuildEvaluator(testCfg()).WithModuleDiffer(differ)
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.Equal(t, []string{"baba"}, result.Retest)
+	require.Equal(t, []string{"cmd/baba"}, result.Redeploy)
+}
+
+func TestBuildEvaluator_Evaluate_ModuleDiff_Workspace_ImportingPackagesOfAllModules(t *testing.T) {
+	packages := moduleDiffPackages(true)
+	changes := []info.ChangeInfo{
+		{
+			Status: info.StatusModified,
+			Path:   "go.mod",
+		},
+	}
+
+	differ := FakeModuleDiffer{changes: info.ModuleChanges{
+		Modules:  []string{"gopkg.in/yaml.v3"},
+		Required: []string{"gopkg.in/yaml.v3", "github.com/stretchr/testify"},
+	}}
+	eval := evaluate.NewBuildEvaluator(testCfg()).WithModuleDiffer(differ)
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.ElementsMatch(t, []string{"baba", "tools/lint"}, result.Retest)
+	require.Equal(t, []string{"cmd/baba"}, result.Redeploy)
+}
+
+func TestBuildEvaluator_Evaluate_ModuleDiffFullScale_FullRedeploy(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/baba",
+			Dependencies: []string{"baba"},
+		},
+		{
+			Path:          "baba",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Status: info.StatusModified,
+			Path:   "go.mod",
+		},
+	}
+
+	differ := FakeModuleDiffer{changes: info.ModuleChanges{FullScale: true}}
+	eval := evaluate.NewBuildEvaluator(testCfg()).WithModuleDiffer(differ)
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.Equal(t, []string{"baba"}, result.Retest)
+	require.Equal(t, []string{"cmd/baba"}, result.Redeploy)
+}
+
+func TestBuildEvaluator_Evaluate_WorkspaceFile_FullScale(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/baba",
+			Dependencies: []string{"baba"},
+		},
+		{
+			Path:          "baba",
+			ContainsTests: true,
+		},
+	}
+
+	for _, path := range []string{"go.work", "go.work.sum"} {
+		t.Run(path, func(t *testing.T) {
+			changes := []info.ChangeInfo{
+				{
+					Status: info.StatusModified,
+					Path:   path,
+				},
+			}
+
+			eval := evaluate.NewBuildEvaluator(testCfg())
+			result, errEval := eval.Evaluate(packages, changes)
+
+			require.NoError(t, errEval)
+
+			require.True(t, result.FullScale)
+			require.Equal(t, []string{"baba"}, result.Retest)
+			require.Equal(t, []string{"cmd/baba"}, result.Redeploy)
+			require.Equal(t, "workspace file changed", result.SpecialCases[0].Trigger)
+		})
+	}
+}
+
+func TestBuildEvaluator_Evaluate_IgnoredTargetFiles_NothingHappens(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/baba",
+			Files:        []string{"cmd/baba/main.go"},
+			Dependencies: []string{"baba"},
+		},
+		{
+			Path:          "cmd/windows",
+			IgnoredFiles:  []string{"cmd/windows/main.go"},
+			ContainsTests: true,
+		},
+		{
+			Path:          "baba",
+			Files:         []string{"baba/baba.go"},
+			IgnoredFiles:  []string{"baba/baba_windows.go"},
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Status: info.StatusModified,
+			Path:   "baba/baba_windows.go",
+		},
+		{
+			Status: info.StatusModified,
+			Path:   "cmd/windows/Dockerfile",
+		},
+	}
+
+	eval := evaluate.NewBuildEvaluator(testCfg())
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.Empty(t, result.Retest)
+	require.Empty(t, result.Redeploy)
+}
+
+func TestBuildEvaluator_Evaluate_FullScale_SkipsExcludedPackages(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:          "cmd/baba",
+			Files:         []string{"cmd/baba/main.go"},
+			ContainsTests: true,
+		},
+		{
+			Path:          "cmd/windows",
+			IgnoredFiles:  []string{"cmd/windows/main.go"},
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Status: info.StatusModified,
+			Path:   "go.mod",
+		},
+	}
+
+	eval := evaluate.NewBuildEvaluator(evaluate.NewConfig("cmd/", nil, []string{"go.mod$"}))
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.Equal(t, []string{"cmd/baba"}, result.Retest)
+	require.Equal(t, []string{"cmd/baba"}, result.Redeploy)
+}
+
+func TestEvaluation_Merge_KeepsOrderWithoutDuplicates(t *testing.T) {
+	first := evaluate.Evaluation{
+		Retest:   []string{"baba", "common"},
+		Redeploy: []string{"cmd/baba"},
+	}
+	second := evaluate.Evaluation{
+		Retest:   []string{"common", "windows"},
+		Redeploy: []string{"cmd/windows", "cmd/baba"},
+		Neutral:  []string{"baba/doc.go"},
+	}
+
+	result := first.Merge(second)
+
+	require.Equal(t, []string{"baba", "common", "windows"}, result.Retest)
+	require.Equal(t, []string{"cmd/baba", "cmd/windows"}, result.Redeploy)
+	require.Equal(t, []string{"baba/doc.go"}, result.Neutral)
+}
+
+func TestBuildEvaluator_Evaluate_ChangedTestHelper_OnlyRetestsTestDependants(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:             "cmd/baba",
+			Dependencies:     []string{"baba"},
+			TestDependencies: []string{"testutil"},
+			ContainsTests:    true,
+		},
+		{
+			Path:             "baba",
+			TestDependencies: []string{"testutil"},
+			ContainsTests:    true,
+		},
+		{
+			Path:         "testutil",
+			Dependencies: []string{"common"},
+		},
+		{
+			Path:          "common",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Status: info.StatusModified,
+			Path:   "common/errors.go",
+		},
+	}
+
+	eval := evaluate.NewBuildEvaluator(testCfg())
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.ElementsMatch(t, []string{"common", "baba", "cmd/baba"}, result.Retest)
+	require.Empty(t, result.Redeploy)
+}
+
+func TestBuildEvaluator_Evaluate_EmbeddedAsset_DirtiesEmbeddingPackages(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/web",
+			Dependencies: []string{"web"},
+		},
+		{
+			Path:         "cmd/other",
+			Dependencies: []string{"web/templates"},
+		},
+		{
+			Path:          "web",
+			ContainsTests: true,
+			Embeds:        []string{"templates/*.html"},
+			TestEmbeds:    []string{"testdata"},
+		},
+		{
+			Path:          "web/templates",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Status: info.StatusModified,
+			Path:   "web/templates/index.html",
+		},
+	}
+
+	eval := evaluate.NewBuildEvaluator(testCfg())
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.Equal(t, []string{"web"}, result.Retest)
+	require.Equal(t, []string{"cmd/web"}, result.Redeploy)
+}
+
+func TestBuildEvaluator_Evaluate_TestEmbeddedAsset_OnlyRetests(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/web",
+			Dependencies: []string{"web"},
+		},
+		{
+			Path:          "web",
+			ContainsTests: true,
+			TestEmbeds:    []string{"testdata"},
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Status: info.StatusModified,
+			Path:   "web/testdata/golden.txt",
+		},
+	}
+
+	eval := evaluate.NewBuildEvaluator(testCfg())
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.Equal(t, []string{"web"}, result.Retest)
+	require.Empty(t, result.Redeploy)
+}
+
+func TestBuildEvaluator_Evaluate_OwnedFile_DirtiesOwners(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/migrator",
+			Dependencies: []string{"store"},
+		},
+		{
+			Path:         "cmd/api",
+			Dependencies: []string{"store"},
+		},
+		{
+			Path:          "store",
+			ContainsTests: true,
+		},
+		{
+			Path:          "db",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Status: info.StatusAdded,
+			Path:   "db/migrations/0001_init.sql",
+		},
+		{
+			Status: info.StatusModified,
+			Path:   "db/schema.sql",
+		},
+	}
+
+	cfg := testCfg().WithOwnership(
+		evaluate.NewOwnershipRule("^db/migrations/", "cmd/migrator"),
+		evaluate.NewOwnershipRule(`^db/.*\.sql$`, "cmd/migrator"))
+	eval := evaluate.NewBuildEvaluator(cfg)
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.Empty(t, result.Retest)
+	require.Equal(t, []string{"cmd/migrator"}, result.Redeploy)
+}
+
+func TestBuildEvaluator_Evaluate_UnknownOwner_Error(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path: "cmd/api",
+		},
+	}
+
+	cfg := testCfg().WithOwnership(evaluate.NewOwnershipRule("^db/", "cmd/migrator"))
+	eval := evaluate.NewBuildEvaluator(cfg)
+	_, errEval := eval.Evaluate(packages, nil)
+
+	require.Error(t, errEval)
+	require.Contains(t, errEval.Error(), "cmd/migrator")
+}
+
+func TestBuildEvaluator_Evaluate_SharedDependencies_RecordsReasonChains(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/baba",
+			Dependencies: []string{"baba"},
+		},
+		{
+			Path:          "baba",
+			ContainsTests: true,
+			Dependencies:  []string{"common"},
+		},
+		{
+			Path:          "common",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Path: "common/errors.go",
+		},
+	}
+
+	eval := evaluate.NewBuildEvaluator(testCfg())
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.Equal(t, "common/errors.go -> common", result.Reasons["common"].String())
+	require.Equal(t, "common/errors.go -> common -> baba", result.Reasons["baba"].String())
+	require.Equal(t, "common/errors.go -> common -> baba -> cmd/baba", result.Reasons["cmd/baba"].String())
+}
+
+func TestBuildEvaluator_Evaluate_SpecialCase_RecordsTrigger(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:          "baba",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Path: "helm/any_file",
+		},
+	}
+
+	cfg := evaluate.NewConfig("cmd/", []string{"helm/.*"}, nil)
+	eval := evaluate.NewBuildEvaluator(cfg)
+
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	reason := result.Reasons["baba"]
+	require.Equal(t, "helm/any_file", reason.Change)
+	require.Equal(t, "retest trigger helm/.*", reason.Trigger)
+	require.Empty(t, reason.Chain)
+
+	require.Equal(t, []evaluate.Reason{reason}, result.SpecialCases)
+	require.False(t, result.FullScale)
+}
+
+func TestBuildEvaluator_Evaluate_FullScaleTrigger_RecordsFullScale(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/baba",
+			Dependencies: []string{"baba"},
+		},
+		{
+			Path:          "baba",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Path: "go.mod",
+		},
+	}
+
+	cfg := evaluate.NewConfig("cmd/", nil, []string{"go.mod$"})
+	eval := evaluate.NewBuildEvaluator(cfg)
+
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+	require.True(t, result.FullScale)
+	require.Len(t, result.SpecialCases, 1)
+	require.Equal(t, "go.mod (full scale trigger go.mod$)", result.SpecialCases[0].String())
+}
+
+func TestEvaluation_Merge_KeepsSpecialCasesWithoutDuplicates(t *testing.T) {
+	first := evaluate.Evaluation{
+		SpecialCases: []evaluate.Reason{{Change: "go.mod", Trigger: "full scale trigger go.mod$"}},
+		FullScale:    true,
+	}
+	second := evaluate.Evaluation{
+		SpecialCases: []evaluate.Reason{
+			{Change: "go.mod", Trigger: "full scale trigger go.mod$"},
+			{Change: "helm/values.yaml", Trigger: "retest trigger helm/.*"},
+		},
+	}
+
+	merged := first.Merge(second)
+
+	require.True(t, merged.FullScale)
+	require.Len(t, merged.SpecialCases, 2)
+	require.Equal(t, "helm/values.yaml", merged.SpecialCases[1].Change)
+}
+
+func TestBuildEvaluator_Evaluate_ListsTestableAndDeployablePackages(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/baba",
+			Dependencies: []string{"baba"},
+		},
+		{
+			Path:          "baba",
+			ContainsTests: true,
+		},
+		{
+			Path: "mocks",
+		},
+		{
+			Path:          "cmd/is",
+			ContainsTests: true,
+			IgnoredFiles:  []string{"cmd/is/is_windows.go"},
+		},
+	}
+
+	eval := evaluate.NewBuildEvaluator(testCfg())
+	result, errEval := eval.Evaluate(packages, nil)
+
+	require.NoError(t, errEval)
+	require.Equal(t, []string{"baba"}, result.Testable)
+	require.Equal(t, []string{"cmd/baba"}, result.Deployable)
+	require.Equal(t, []string{"cmd/baba", "baba", "mocks", "cmd/is"}, result.Packages)
+	require.Equal(t, []string{"cmd/is"}, result.Excluded)
+}
+
+type (
+	FakeCoverage struct {
+		tests map[string][]string
+	}
+)
+
+func (c FakeCoverage) TestsCovering(change info.ChangeInfo) ([]string, bool) {
+	tests, ok := c.tests[change.Path]
+	return tests, ok
+}
+
+func TestBuildEvaluator_Evaluate_Coverage_OnlyRetestsCoveringPackages(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/baba",
+			Dependencies: []string{"baba"},
+		},
+		{
+			Path:          "baba",
+			ContainsTests: true,
+			Dependencies:  []string{"common"},
+		},
+		{
+			Path:          "other",
+			ContainsTests: true,
+			Dependencies:  []string{"common"},
+		},
+		{
+			Path:          "common",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Path: "common/errors.go",
+		},
+	}
+
+	coverage := FakeCoverage{tests: map[string][]string{"common/errors.go": {"other"}}}
+	eval := evaluate.NewBuildEvaluator(testCfg()).WithCoverage(coverage)
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.Equal(t, []string{"other"}, result.Retest)
+	require.Equal(t, []string{"cmd/baba"}, result.Redeploy)
+	require.Equal(t, "common/errors.go (covered by the tests)", result.Reasons["other"].String())
+}
+
+func TestBuildEvaluator_Evaluate_UncoveredFile_FallsBackToGraph(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:          "baba",
+			ContainsTests: true,
+			Dependencies:  []string{"common"},
+		},
+		{
+			Path:          "common",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Path: "common/new.go",
+		},
+	}
+
+	coverage := FakeCoverage{tests: map[string][]string{"common/errors.go": {"baba"}}}
+	eval := evaluate.NewBuildEvaluator(testCfg()).WithCoverage(coverage)
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+	require.ElementsMatch(t, []string{"baba", "common"}, result.Retest)
+}
+
+type (
+	FakeTestSelector struct {
+		tests map[string][]string
+	}
+)
+
+func (s FakeTestSelector) ChangedTests(change info.ChangeInfo) ([]string, bool) {
+	tests, ok := s.tests[change.Path]
+	return tests, ok
+}
+
+func TestBuildEvaluator_Evaluate_ChangedTests_SelectsTestFunctions(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:          "baba",
+			ContainsTests: true,
+		},
+		{
+			Path:          "is",
+			ContainsTests: true,
+		},
+		{
+			Path:          "you",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{Path: "baba/baba_test.go", Status: info.StatusModified},
+		{Path: "baba/is_test.go", Status: info.StatusModified},
+		{Path: "is/is_test.go", Status: info.StatusModified},
+		{Path: "is/is.go", Status: info.StatusModified},
+		{Path: "you/you_test.go", Status: info.StatusModified},
+	}
+
+	selector := FakeTestSelector{tests: map[string][]string{
+		"baba/baba_test.go": {"TestBaba"},
+		"baba/is_test.go":   {"TestIs", "TestBaba"},
+		"is/is_test.go":     {"TestIs"},
+	}}
+	eval := evaluate.NewBuildEvaluator(testCfg()).WithTestSelector(selector)
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.ElementsMatch(t, []string{"baba", "is", "you"}, result.Retest)
+	require.Equal(t, map[string][]string{"baba": {"TestBaba", "TestIs"}}, result.TestFunctions)
+}
+
+func TestEvaluation_Merge_TestFunctions(t *testing.T) {
+	first := evaluate.Evaluation{
+		Retest:        []string{"baba", "is", "you"},
+		TestFunctions: map[string][]string{"baba": {"TestBaba"}, "is": {"TestIs"}},
+	}
+	second := evaluate.Evaluation{
+		Retest:        []string{"baba", "is", "flag"},
+		TestFunctions: map[string][]string{"baba": {"TestAlso"}, "flag": {"TestFlag"}},
+	}
+
+	merged := first.Merge(second)
+
+	require.Equal(t, map[string][]string{
+		"baba": {"TestAlso", "TestBaba"},
+		"flag": {"TestFlag"},
+	}, merged.TestFunctions)
+}
+
+func TestBuildEvaluator_Evaluate_DeploymentInputs_OnlyRedeployDeclaringDeployment(t *testing.T) {
+	manifest := &info.DeploymentManifest{Inputs: []string{"/deploy/baba.yaml"}, Team: "baba"}
+	packages := []info.PackageInfo{
+		{
+			Path:         "cmd/baba",
+			Dependencies: []string{"baba"},
+			Manifest:     manifest,
+		},
+		{
+			Path:         "cmd/is",
+			Dependencies: []string{"baba"},
+		},
+		{
+			Path:          "baba",
+			ContainsTests: true,
+		},
+	}
+	changes := []info.ChangeInfo{
+		{
+			Path:   "deploy/baba.yaml",
+			Status: info.StatusModified,
+		},
+	}
+
+	eval := evaluate.NewBuildEvaluator(testCfg())
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.Empty(t, result.Retest)
+	require.Equal(t, []string{"cmd/baba"}, result.Redeploy)
+	require.Equal(t, map[string]info.DeploymentManifest{"cmd/baba": *manifest}, result.Deployments)
+	require.Equal(t, "deploy/baba.yaml -> cmd/baba", result.Reasons["cmd/baba"].String())
+}
+
+func scopedTriggerPackages() []info.PackageInfo {
+	return []info.PackageInfo{
+		{
+			Path:         "cmd/billing-api",
+			Dependencies: []string{"billing/invoice"},
+		},
+		{
+			Path:         "cmd/shop",
+			Dependencies: []string{"shop"},
+		},
+		{
+			Path:          "billing/invoice",
+			ContainsTests: true,
+		},
+		{
+			Path:          "shop",
+			ContainsTests: true,
+			Dependencies:  []string{"billing/invoice"},
+		},
+	}
+}
+
+func TestBuildEvaluator_Evaluate_ScopedTrigger_OnlySelectedPackages(t *testing.T) {
+	changes := []info.ChangeInfo{
+		{
+			Path:   "proto/billing/invoice.proto",
+			Status: info.StatusModified,
+		},
+	}
+
+	cfg := testCfg().WithScopedTriggers(
+		evaluate.NewScopedTrigger("^proto/billing/", []string{"billing/..."}, []string{"cmd/billing-*"}, false))
+	eval := evaluate.NewBuildEvaluator(cfg)
+	result, errEval := eval.Evaluate(scopedTriggerPackages(), changes)
+
+	require.NoError(t, errEval)
+
+	require.Equal(t, []string{"billing/invoice"}, result.Retest)
+	require.Equal(t, []string{"cmd/billing-api"}, result.Redeploy)
+	require.Empty(t, result.SpecialCases)
+	require.Equal(t, "proto/billing/invoice.proto (scoped trigger ^proto/billing/)", result.Reasons["billing/invoice"].String())
+}
+
+func TestBuildEvaluator_Evaluate_ScopedTrigger_GoFile_AddsToPackageEvaluation(t *testing.T) {
+	packages := append(scopedTriggerPackages(),
+		info.PackageInfo{
+			Path:          "proto/billing",
+			ContainsTests: true,
+		},
+		info.PackageInfo{
+			Path:         "cmd/billing",
+			Dependencies: []string{"proto/billing"},
+		},
+	)
+	changes := []info.ChangeInfo{
+		{
+			Path:   "proto/billing/billing.pb.go",
+			Status: info.StatusModified,
+		},
+	}
+
+	cfg := testCfg().WithScopedTriggers(
+		evaluate.NewScopedTrigger("^proto/billing/", []string{"billing/..."}, nil, false))
+	eval := evaluate.NewBuildEvaluator(cfg)
+	result, errEval := eval.Evaluate(packages, changes)
+
+	require.NoError(t, errEval)
+
+	require.ElementsMatch(t, []string{"billing/invoice", "proto/billing"}, result.Retest)
+	require.Equal(t, []string{"cmd/billing"}, result.Redeploy)
+}
+
+func TestBuildEvaluator_Evaluate_ScopedTrigger_Propagate(t *testing.T) {
+	changes := []info.ChangeInfo{
+		{
+			Path:   "proto/billing/invoice.proto",
+			Status: info.StatusModified,
+		},
+	}
+
+	cfg := testCfg().WithScopedTriggers(
+		evaluate.NewScopedTrigger("^proto/billing/", []string{"billing/invoice"}, []string{"shop"}, true))
+	eval := evaluate.NewBuildEvaluator(cfg)
+	result, errEval := eval.Evaluate(scopedTriggerPackages(), changes)
+
+	require.NoError(t, errEval)
+
+	require.ElementsMatch(t, []string{"billing/invoice", "shop"}, result.Retest)
+	require.Equal(t, []string{"cmd/shop"}, result.Redeploy)
+}
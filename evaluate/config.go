@@ -1,17 +1,55 @@
 package evaluate
 
-import "regexp"
+import (
+	"github.com/go-lean/bevaluate/info"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
 
 type (
 	Config struct {
 		DeploymentsDir string
 		SpecialCases   SpecialCases
+		Ownership      []OwnershipRule
+		Deployables    DeployableRules
+	}
+
+	// DeployableRules decide which packages are deployed. A package is deployable when it is included,
+	// or when it is not excluded and satisfies every configured criterion. Without any criterion it has
+	// to be inside the deployments dir instead.
+	DeployableRules struct {
+		MainPackages bool
+		Markers      []string
+		Patterns     []*regexp.Regexp
+		Include      []string
+		Exclude      []string
+	}
+
+	OwnershipRule struct {
+		Match  *regexp.Regexp
+		Owners []string
 	}
 
 	SpecialCases struct {
 		RetestTriggers    []*regexp.Regexp
 		FullScaleTriggers []*regexp.Regexp
+		ScopedTriggers    []ScopedTrigger
 	}
+
+	// ScopedTrigger marks only the selected packages when a changed path matches. The packages are
+	// selected by directory, a trailing /... selecting the whole subtree, or by a glob.
+	ScopedTrigger struct {
+		Match     *regexp.Regexp
+		Retest    []string
+		Redeploy  []string
+		Propagate bool
+	}
+)
+
+const (
+	subtreeSuffix = "/..."
 )
 
 func NewConfig(deploymentsDir string, specialRetestCases, specialRedeployCases []string) Config {
@@ -44,3 +82,145 @@ func NewConfig(deploymentsDir string, specialRetestCases, specialRedeployCases [
 		},
 	}
 }
+
+func NewOwnershipRule(match string, owners ...string) OwnershipRule {
+	exp, errCompile := regexp.Compile(match)
+	if errCompile != nil {
+		panic("could not compile ownership rule: " + errCompile.Error())
+	}
+
+	return OwnershipRule{
+		Match:  exp,
+		Owners: owners,
+	}
+}
+
+func (c Config) WithOwnership(rules ...OwnershipRule) Config {
+	c.Ownership = rules
+	return c
+}
+
+func NewScopedTrigger(match string, retest, redeploy []string, propagate bool) ScopedTrigger {
+	exp, errCompile := regexp.Compile(match)
+	if errCompile != nil {
+		panic("could not compile scoped trigger: " + errCompile.Error())
+	}
+
+	for _, pattern := range append(append([]string{}, retest...), redeploy...) {
+		if _, errPattern := path.Match(pattern, ""); errPattern != nil {
+			panic("could not parse scoped trigger package pattern: " + pattern)
+		}
+	}
+
+	return ScopedTrigger{
+		Match:     exp,
+		Retest:    retest,
+		Redeploy:  redeploy,
+		Propagate: propagate,
+	}
+}
+
+func (c Config) WithScopedTriggers(triggers ...ScopedTrigger) Config {
+	c.SpecialCases.ScopedTriggers = triggers
+	return c
+}
+
+func NewDeployableRules(mainPackages bool, markers, patterns, include, exclude []string) DeployableRules {
+	expressions := make([]*regexp.Regexp, len(patterns))
+
+	for i, pattern := range patterns {
+		exp, errCompile := regexp.Compile(pattern)
+		if errCompile != nil {
+			panic("could not compile deployable pattern: " + errCompile.Error())
+		}
+
+		expressions[i] = exp
+	}
+
+	return DeployableRules{
+		MainPackages: mainPackages,
+		Markers:      markers,
+		Patterns:     expressions,
+		Include:      include,
+		Exclude:      exclude,
+	}
+}
+
+func (r DeployableRules) selects() bool {
+	return r.MainPackages || len(r.Markers) > 0 || len(r.Patterns) > 0
+}
+
+func (c Config) WithDeployables(rules DeployableRules) Config {
+	c.Deployables = rules
+	return c
+}
+
+func (c Config) IsDeployable(pkg info.PackageInfo) bool {
+	rules := c.Deployables
+
+	if contains(rules.Include, pkg.Path) {
+		return true
+	}
+
+	if contains(rules.Exclude, pkg.Path) {
+		return false
+	}
+
+	if rules.selects() == false {
+		return strings.HasPrefix(pkg.Path, c.DeploymentsDir)
+	}
+
+	if rules.MainPackages && pkg.IsMain() == false {
+		return false
+	}
+
+	if len(rules.Markers) > 0 && hasMarker(pkg, rules.Markers) == false {
+		return false
+	}
+
+	if len(rules.Patterns) > 0 && matchesAny(rules.Patterns, pkg.Path) == false {
+		return false
+	}
+
+	return true
+}
+
+func hasMarker(pkg info.PackageInfo, markers []string) bool {
+	for _, file := range pkg.OtherFiles {
+		for _, marker := range markers {
+			if matched, _ := filepath.Match(marker, filepath.Base(file)); matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func matchesAny(expressions []*regexp.Regexp, path string) bool {
+	for _, exp := range expressions {
+		if exp.MatchString(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesPackage(patterns []string, pkg string) bool {
+	for _, pattern := range patterns {
+		if prefix := strings.TrimSuffix(pattern, subtreeSuffix); prefix != pattern {
+			if prefix == "" || pkg == prefix || strings.HasPrefix(pkg, prefix+"/") {
+				return true
+			}
+
+			continue
+		}
+
+		if matched, _ := path.Match(pattern, pkg); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,6 +2,7 @@ package evaluate_test
 
 import (
 	"github.com/go-lean/bevaluate/evaluate"
+	"github.com/go-lean/bevaluate/info"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -17,3 +18,88 @@ func TestNewConfig_BadRedeployExpression_Panic(t *testing.T) {
 		_ = evaluate.NewConfig("cmd/", nil, []string{"["})
 	})
 }
+
+func TestNewOwnershipRule_BadExpression_Panic(t *testing.T) {
+	require.Panics(t, func() {
+		_ = evaluate.NewOwnershipRule("[", "cmd/baba")
+	})
+}
+
+func TestNewDeployableRules_BadExpression_Panic(t *testing.T) {
+	require.Panics(t, func() {
+		_ = evaluate.NewDeployableRules(false, nil, []string{"["}, nil, nil)
+	})
+}
+
+func TestConfig_IsDeployable(t *testing.T) {
+	mainPkg := info.PackageInfo{Path: "services/baba", Name: "main", OtherFiles: []string{"services/baba/Dockerfile"}}
+	helperPkg := info.PackageInfo{Path: "services/baba/flags", Name: "flags"}
+	toolPkg := info.PackageInfo{Path: "tools/is", Name: "main"}
+
+	testCases := []struct {
+		name           string
+		deploymentsDir string
+		rules          evaluate.DeployableRules
+		expected       []bool
+	}{
+		{
+			name:           "deployments dir should select everything inside",
+			deploymentsDir: "services/",
+			expected:       []bool{true, true, false},
+		},
+		{
+			name:           "rules should replace the deployments dir",
+			deploymentsDir: "services/",
+			rules:          evaluate.NewDeployableRules(true, nil, nil, nil, nil),
+			expected:       []bool{true, false, true},
+		},
+		{
+			name:           "include and exclude alone should keep the deployments dir",
+			deploymentsDir: "services/",
+			rules:          evaluate.NewDeployableRules(false, nil, nil, []string{"tools/is"}, []string{"services/baba/flags"}),
+			expected:       []bool{true, false, true},
+		},
+		{
+			name:     "main packages should skip helpers",
+			rules:    evaluate.NewDeployableRules(true, nil, nil, nil, nil),
+			expected: []bool{true, false, true},
+		},
+		{
+			name:     "markers should require a marker file",
+			rules:    evaluate.NewDeployableRules(false, []string{"Docker*"}, nil, nil, nil),
+			expected: []bool{true, false, false},
+		},
+		{
+			name:     "patterns should require a match",
+			rules:    evaluate.NewDeployableRules(true, nil, []string{"^tools/", "^lambda/"}, nil, nil),
+			expected: []bool{false, false, true},
+		},
+		{
+			name:     "include and exclude should bypass the rules",
+			rules:    evaluate.NewDeployableRules(true, nil, nil, []string{"services/baba/flags"}, []string{"tools/is"}),
+			expected: []bool{true, true, false},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := evaluate.NewConfig(c.deploymentsDir, nil, nil).WithDeployables(c.rules)
+
+			actual := []bool{cfg.IsDeployable(mainPkg), cfg.IsDeployable(helperPkg), cfg.IsDeployable(toolPkg)}
+
+			require.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestNewScopedTrigger_BadExpression_Panic(t *testing.T) {
+	require.Panics(t, func() {
+		_ = evaluate.NewScopedTrigger("[", nil, nil, false)
+	})
+}
+
+func TestNewScopedTrigger_BadPackagePattern_Panic(t *testing.T) {
+	require.Panics(t, func() {
+		_ = evaluate.NewScopedTrigger("^proto/", []string{"billing/["}, nil, false)
+	})
+}
@@ -3,6 +3,7 @@ package evaluate
 import (
 	"fmt"
 	"github.com/go-lean/bevaluate/info"
+	"sort"
 )
 
 type (
@@ -13,9 +14,13 @@ type (
 
 	DependencyNode struct {
 		info.PackageInfo
-		Dependants []*DependencyNode
-		retest     bool
-		redeploy   bool
+		Dependants     []*DependencyNode
+		TestDependants []*DependencyNode
+		retest         bool
+		redeploy       bool
+		reason         *Reason
+		wholeRetest    bool
+		testFunctions  map[string]struct{}
 	}
 )
 
@@ -46,7 +51,63 @@ func (g DependencyGraph) Build() error {
 
 			dependencyNode.Dependants = append(dependencyNode.Dependants, node)
 		}
+
+		for _, dependency := range node.TestDependencies {
+			dependencyNode, ok := g.NodesMap[dependency]
+			if ok == false {
+				return fmt.Errorf("could not find test dependency node with path: %q", dependency)
+			}
+
+			dependencyNode.TestDependants = append(dependencyNode.TestDependants, node)
+		}
 	}
 
 	return nil
 }
+
+func (n *DependencyNode) markRetest(reason Reason) {
+	n.retest = true
+	n.wholeRetest = true
+	n.explain(reason)
+}
+
+func (n *DependencyNode) markRetestFunctions(reason Reason, functions []string) {
+	n.retest = true
+	n.explain(reason)
+
+	if n.testFunctions == nil {
+		n.testFunctions = make(map[string]struct{}, len(functions))
+	}
+
+	for _, function := range functions {
+		n.testFunctions[function] = struct{}{}
+	}
+}
+
+// retestFunctions returns the test functions to run, or nil when the whole package is retested.
+func (n *DependencyNode) retestFunctions() []string {
+	if n.retest == false || n.wholeRetest || len(n.testFunctions) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(n.testFunctions))
+	for function := range n.testFunctions {
+		result = append(result, function)
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+func (n *DependencyNode) markRedeploy(reason Reason) {
+	n.redeploy = true
+	n.explain(reason)
+}
+
+func (n *DependencyNode) explain(reason Reason) {
+	if n.reason != nil {
+		return // the first reason is kept
+	}
+
+	n.reason = &reason
+}
@@ -148,3 +148,39 @@ func TestDependencyGraph_Build_AllDependOnOne(t *testing.T) {
 	require.Equal(t, g.NodesMap["other"], g.NodesMap["dal"].Dependants[1])
 	require.Equal(t, g.NodesMap["common"], g.NodesMap["dal"].Dependants[2])
 }
+
+func TestDependencyGraph_Build_MissingTestPackage_Error(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:             "baba",
+			TestDependencies: []string{"verymissing"},
+		},
+	}
+
+	g := evaluate.NewDependencyGraph(packages)
+	err := g.Build()
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "verymissing")
+}
+
+func TestDependencyGraph_Build_TestDependency_OneTestDependant(t *testing.T) {
+	packages := []info.PackageInfo{
+		{
+			Path:             "baba",
+			TestDependencies: []string{"testutil"},
+		},
+		{
+			Path: "testutil",
+		},
+	}
+
+	g := evaluate.NewDependencyGraph(packages)
+	err := g.Build()
+
+	require.NoError(t, err)
+
+	require.Empty(t, g.NodesMap["testutil"].Dependants)
+	require.Len(t, g.NodesMap["testutil"].TestDependants, 1)
+	require.Equal(t, "baba", g.NodesMap["testutil"].TestDependants[0].Path)
+}
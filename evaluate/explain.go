@@ -0,0 +1,98 @@
+package evaluate
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	Reason struct {
+		Change  string   `json:"change" yaml:"change"`
+		Trigger string   `json:"trigger,omitempty" yaml:"trigger,omitempty"`
+		Chain   []string `json:"chain,omitempty" yaml:"chain,omitempty,flow"`
+	}
+
+	Explanation struct {
+		Package  string  `json:"package"`
+		Retest   bool    `json:"retest"`
+		Redeploy bool    `json:"redeploy"`
+		Reason   *Reason `json:"reason,omitempty"`
+	}
+
+	specialCaseError struct {
+		err     error
+		trigger string
+	}
+)
+
+const (
+	chainSeparator = " -> "
+)
+
+func (r Reason) String() string {
+	if r.Trigger != "" {
+		return fmt.Sprintf("%s (%s)", r.Change, r.Trigger)
+	}
+
+	return strings.Join(append([]string{r.Change}, r.Chain...), chainSeparator)
+}
+
+func (r Reason) via(path string) Reason {
+	chain := make([]string, 0, len(r.Chain)+1)
+	chain = append(chain, r.Chain...)
+
+	return Reason{
+		Change:  r.Change,
+		Trigger: r.Trigger,
+		Chain:   append(chain, path),
+	}
+}
+
+func (e Evaluation) Explain(pkg string) Explanation {
+	result := Explanation{
+		Package:  pkg,
+		Retest:   contains(e.Retest, pkg),
+		Redeploy: contains(e.Redeploy, pkg),
+	}
+
+	if reason, ok := e.Reasons[pkg]; ok && (result.Retest || result.Redeploy) {
+		result.Reason = &reason
+	}
+
+	return result
+}
+
+func (e Explanation) String() string {
+	if e.Reason == nil {
+		return fmt.Sprintf("%s: not selected", e.Package)
+	}
+
+	actions := make([]string, 0, 2)
+	if e.Retest {
+		actions = append(actions, "retest")
+	}
+
+	if e.Redeploy {
+		actions = append(actions, "redeploy")
+	}
+
+	return fmt.Sprintf("%s [%s]: %s", e.Package, strings.Join(actions, ", "), e.Reason)
+}
+
+func (e specialCaseError) Error() string {
+	return fmt.Sprintf("%v: %s", e.err, e.trigger)
+}
+
+func (e specialCaseError) Unwrap() error {
+	return e.err
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+
+	return false
+}
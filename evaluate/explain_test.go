@@ -0,0 +1,39 @@
+package evaluate_test
+
+import (
+	"github.com/go-lean/bevaluate/evaluate"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestEvaluation_Explain_SelectedPackage(t *testing.T) {
+	evaluation := evaluate.Evaluation{
+		Retest:   []string{"baba"},
+		Redeploy: []string{"cmd/baba"},
+		Reasons: map[string]evaluate.Reason{
+			"cmd/baba": {Change: "common/errors.go", Chain: []string{"common", "baba", "cmd/baba"}},
+		},
+	}
+
+	explanation := evaluation.Explain("cmd/baba")
+
+	require.False(t, explanation.Retest)
+	require.True(t, explanation.Redeploy)
+	require.NotNil(t, explanation.Reason)
+	require.Equal(t, "cmd/baba [redeploy]: common/errors.go -> common -> baba -> cmd/baba", explanation.String())
+}
+
+func TestEvaluation_Explain_NotSelected(t *testing.T) {
+	evaluation := evaluate.Evaluation{Retest: []string{"baba"}}
+
+	explanation := evaluation.Explain("other")
+
+	require.Nil(t, explanation.Reason)
+	require.Equal(t, "other: not selected", explanation.String())
+}
+
+func TestReason_String_Trigger(t *testing.T) {
+	reason := evaluate.Reason{Change: "go.mod", Trigger: "full scale trigger go.mod$"}
+
+	require.Equal(t, "go.mod (full scale trigger go.mod$)", reason.String())
+}
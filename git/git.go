@@ -0,0 +1,224 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/go-lean/bevaluate/info"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+type (
+	Client struct {
+		runner Runner
+	}
+
+	Runner interface {
+		Run(args ...string) (string, error)
+	}
+
+	CommandRunner struct {
+		Dir string
+	}
+
+	Range struct {
+		Base        string
+		Head        string
+		MergeBase   bool
+		Uncommitted bool
+		Untracked   bool
+	}
+
+	Versions struct {
+		client   Client
+		root     string
+		base     string
+		head     string
+		worktree bool
+	}
+)
+
+const (
+	DefaultHead = "HEAD"
+)
+
+var (
+	ErrMissingBase         = errors.New("missing base revision")
+	ErrUncommittedWithHead = errors.New("uncommitted changes can only be included when head is " + DefaultHead)
+)
+
+func NewClient(runner Runner) Client {
+	return Client{runner: runner}
+}
+
+func (c Client) Changes(r Range) ([]info.ChangeInfo, error) {
+	from, head, errResolve := c.resolve(r)
+	if errResolve != nil {
+		return nil, errResolve
+	}
+
+	// all paths are relative to the working directory of the runner, which may be below the top level
+	args := []string{"diff", "--no-color", "--no-ext-diff", "--unified=0", "--find-renames", "--find-copies", "--relative", from}
+	if r.Uncommitted == false {
+		args = append(args, head)
+	}
+
+	diff, errDiff := c.run(args...)
+	if errDiff != nil {
+		return nil, fmt.Errorf("could not diff revisions: %w", errDiff)
+	}
+
+	changes, errParse := info.ParseUnifiedDiff(diff)
+	if errParse != nil {
+		return nil, fmt.Errorf("could not parse diff: %w", errParse)
+	}
+
+	if r.Untracked == false {
+		return changes, nil
+	}
+
+	untracked, errUntracked := c.UntrackedFiles()
+	if errUntracked != nil {
+		return nil, fmt.Errorf("could not list untracked files: %w", errUntracked)
+	}
+
+	return appendMissingChanges(changes, untracked), nil
+}
+
+func (c Client) Versions(r Range, root string) (Versions, error) {
+	from, head, errResolve := c.resolve(r)
+	if errResolve != nil {
+		return Versions{}, errResolve
+	}
+
+	return Versions{
+		client:   c,
+		root:     root,
+		base:     from,
+		head:     head,
+		worktree: r.Uncommitted,
+	}, nil
+}
+
+func (c Client) MergeBase(base, head string) (string, error) {
+	out, errRun := c.run("merge-base", base, head)
+	if errRun != nil {
+		return "", errRun
+	}
+
+	mergeBase := strings.TrimSpace(out)
+	if mergeBase == "" {
+		return "", fmt.Errorf("no merge base between %q and %q", base, head)
+	}
+
+	return mergeBase, nil
+}
+
+func (c Client) UntrackedFiles() ([]info.ChangeInfo, error) {
+	out, errRun := c.run("ls-files", "--others", "--exclude-standard")
+	if errRun != nil {
+		return nil, errRun
+	}
+
+	lines := strings.Split(out, "\n")
+	result := make([]info.ChangeInfo, 0, len(lines))
+
+	for _, line := range lines {
+		path := strings.TrimSpace(line)
+		if path == "" {
+			continue
+		}
+
+		result = append(result, info.ChangeInfo{Status: info.StatusAdded, Path: path})
+	}
+
+	return result, nil
+}
+
+func (c Client) Show(revision, path string) ([]byte, error) {
+	out, errRun := c.run("show", revision+":./"+path)
+	if errRun != nil {
+		return nil, errRun
+	}
+
+	return []byte(out), nil
+}
+
+func (c Client) resolve(r Range) (string, string, error) {
+	if r.Base == "" {
+		return "", "", ErrMissingBase
+	}
+
+	head := r.Head
+	if head == "" {
+		head = DefaultHead
+	}
+
+	if r.Uncommitted && head != DefaultHead {
+		return "", "", ErrUncommittedWithHead
+	}
+
+	if r.MergeBase == false {
+		return r.Base, head, nil
+	}
+
+	mergeBase, errMergeBase := c.MergeBase(r.Base, head)
+	if errMergeBase != nil {
+		return "", "", fmt.Errorf("could not resolve merge base: %w", errMergeBase)
+	}
+
+	return mergeBase, head, nil
+}
+
+func (c Client) run(args ...string) (string, error) {
+	return c.runner.Run(append([]string{"-c", "core.quotePath=false"}, args...)...)
+}
+
+func (r CommandRunner) Run(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Dir
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if errRun := cmd.Run(); errRun != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), errRun, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+func (v Versions) Old(path string) ([]byte, error) {
+	return v.client.Show(v.base, path)
+}
+
+func (v Versions) New(path string) ([]byte, error) {
+	if v.worktree {
+		return os.ReadFile(filepath.Join(v.root, path))
+	}
+
+	return v.client.Show(v.head, path)
+}
+
+func appendMissingChanges(changes, extra []info.ChangeInfo) []info.ChangeInfo {
+	known := make(map[string]struct{}, len(changes))
+	for _, change := range changes {
+		known[change.Path] = struct{}{}
+	}
+
+	for _, change := range extra {
+		if _, ok := known[change.Path]; ok {
+			continue
+		}
+
+		known[change.Path] = struct{}{}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
@@ -0,0 +1,312 @@
+package git_test
+
+import (
+	"errors"
+	"github.com/go-lean/bevaluate/git"
+	"github.com/go-lean/bevaluate/info"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var (
+	errKaboom = errors.New("kaboom")
+)
+
+const (
+	modifiedPatch = `diff --git a/baba/baba.go b/baba/baba.go
+index 1b2c3d4..5e6f7a8 100644
+--- a/baba/baba.go
++++ b/baba/baba.go
+@@ -3 +3,2 @@ package baba
+-var is = "you"
++var is = "flag"
++var win = true
+`
+	deletedPatch = `diff --git a/is/is.go b/is/is.go
+deleted file mode 100644
+index 1b2c3d4..0000000
+--- a/is/is.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package is
+`
+	addedPatch = `diff --git a/baba/baba.go b/baba/baba.go
+new file mode 100644
+index 0000000..1b2c3d4
+--- /dev/null
++++ b/baba/baba.go
+@@ -0,0 +1 @@
++package baba
+`
+	renamedPatch = `diff --git a/baba/baba.go b/is/baba.go
+similarity index 100%
+rename from baba/baba.go
+rename to is/baba.go
+`
+)
+
+type (
+	FakeRunner struct {
+		outputs map[string]string
+		calls   []string
+	}
+)
+
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{outputs: make(map[string]string)}
+}
+
+func (r *FakeRunner) MockAt(cmd, output string) *FakeRunner {
+	r.outputs[cmd] = output
+	return r
+}
+
+func (r *FakeRunner) Run(args ...string) (string, error) {
+	cmd := strings.Join(args[2:], " ")
+	r.calls = append(r.calls, cmd)
+
+	output, ok := r.outputs[cmd]
+	if ok == false {
+		return "", errKaboom
+	}
+
+	return output, nil
+}
+
+func TestClient_Changes_MissingBase_Error(t *testing.T) {
+	client := git.NewClient(NewFakeRunner())
+
+	changes, err := client.Changes(git.Range{})
+
+	require.ErrorIs(t, err, git.ErrMissingBase)
+	require.Empty(t, changes)
+}
+
+func TestClient_Changes_UncommittedWithOtherHead_Error(t *testing.T) {
+	client := git.NewClient(NewFakeRunner())
+
+	changes, err := client.Changes(git.Range{Base: "main", Head: "feature", Uncommitted: true})
+
+	require.ErrorIs(t, err, git.ErrUncommittedWithHead)
+	require.Empty(t, changes)
+}
+
+func TestClient_Changes_DiffError(t *testing.T) {
+	client := git.NewClient(NewFakeRunner())
+
+	changes, err := client.Changes(git.Range{Base: "main"})
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "kaboom")
+	require.NotEqual(t, "kaboom", err.Error())
+	require.Empty(t, changes)
+}
+
+func TestClient_Changes_BadDiff_Error(t *testing.T) {
+	runner := NewFakeRunner().MockAt("diff --no-color --no-ext-diff --unified=0 --find-renames --find-copies --relative main HEAD", "--- a/baba.go\n+++ b/baba.go\n@@ baba is you @@")
+	client := git.NewClient(runner)
+
+	changes, err := client.Changes(git.Range{Base: "main"})
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid")
+	require.Empty(t, changes)
+}
+
+func TestClient_Changes_OK(t *testing.T) {
+	runner := NewFakeRunner().MockAt("diff --no-color --no-ext-diff --unified=0 --find-renames --find-copies --relative main feature", modifiedPatch+deletedPatch)
+	client := git.NewClient(runner)
+
+	changes, err := client.Changes(git.Range{Base: "main", Head: "feature"})
+
+	require.NoError(t, err)
+	expected := []info.ChangeInfo{
+		{Status: info.StatusModified, Path: "baba/baba.go", Hunks: []info.Hunk{{OldStart: 3, OldLines: 1, NewStart: 3, NewLines: 2}}},
+		{Status: info.StatusDeleted, Path: "is/is.go", Hunks: []info.Hunk{{OldStart: 1, OldLines: 1, NewStart: 0, NewLines: 0}}},
+	}
+	require.Equal(t, expected, changes)
+}
+
+func TestClient_Changes_Renamed_KeepsBothPaths(t *testing.T) {
+	runner := NewFakeRunner().MockAt("diff --no-color --no-ext-diff --unified=0 --find-renames --find-copies --relative main HEAD", renamedPatch)
+	client := git.NewClient(runner)
+
+	changes, err := client.Changes(git.Range{Base: "main"})
+
+	require.NoError(t, err)
+	expected := []info.ChangeInfo{{Status: info.StatusRenamed, OldPath: "baba/baba.go", Path: "is/baba.go"}}
+	require.Equal(t, expected, changes)
+}
+
+func TestClient_Changes_MergeBaseError(t *testing.T) {
+	client := git.NewClient(NewFakeRunner())
+
+	changes, err := client.Changes(git.Range{Base: "main", MergeBase: true})
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "merge base")
+	require.Empty(t, changes)
+}
+
+func TestClient_Changes_MergeBase_DiffsFromMergeBase(t *testing.T) {
+	runner := NewFakeRunner().
+		MockAt("merge-base main HEAD", "abc123\n").
+		MockAt("diff --no-color --no-ext-diff --unified=0 --find-renames --find-copies --relative abc123 HEAD", addedPatch)
+	client := git.NewClient(runner)
+
+	changes, err := client.Changes(git.Range{Base: "main", MergeBase: true})
+
+	require.NoError(t, err)
+	expected := []info.ChangeInfo{{Status: info.StatusAdded, Path: "baba/baba.go", Hunks: []info.Hunk{{OldStart: 0, OldLines: 0, NewStart: 1, NewLines: 1}}}}
+	require.Equal(t, expected, changes)
+}
+
+func TestClient_Changes_UncommittedAndUntracked_DiffsWorkingTree(t *testing.T) {
+	runner := NewFakeRunner().
+		MockAt("diff --no-color --no-ext-diff --unified=0 --find-renames --find-copies --relative main", modifiedPatch).
+		MockAt("ls-files --others --exclude-standard", "baba/baba.go\nyou/you.go\n")
+	client := git.NewClient(runner)
+
+	changes, err := client.Changes(git.Range{Base: "main", Uncommitted: true, Untracked: true})
+
+	require.NoError(t, err)
+	expected := []info.ChangeInfo{
+		{Status: info.StatusModified, Path: "baba/baba.go", Hunks: []info.Hunk{{OldStart: 3, OldLines: 1, NewStart: 3, NewLines: 2}}},
+		{Status: info.StatusAdded, Path: "you/you.go"},
+	}
+	require.Equal(t, expected, changes)
+}
+
+func TestClient_Changes_UntrackedError(t *testing.T) {
+	runner := NewFakeRunner().MockAt("diff --no-color --no-ext-diff --unified=0 --find-renames --find-copies --relative main HEAD", "")
+	client := git.NewClient(runner)
+
+	changes, err := client.Changes(git.Range{Base: "main", Untracked: true})
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "untracked")
+	require.Empty(t, changes)
+}
+
+func TestCommandRunner_Run_Error(t *testing.T) {
+	runner := git.CommandRunner{Dir: t.TempDir()}
+
+	out, err := runner.Run("baba-is-you")
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "baba-is-you")
+	require.Empty(t, out)
+}
+
+func TestCommandRunner_Run_OK(t *testing.T) {
+	dir := t.TempDir()
+	runner := git.CommandRunner{Dir: dir}
+
+	_, errInit := runner.Run("init", "--quiet")
+	require.NoError(t, errInit)
+
+	errWrite := os.WriteFile(filepath.Join(dir, "baba.go"), []byte("package baba"), os.ModePerm)
+	require.NoError(t, errWrite)
+
+	changes, err := git.NewClient(runner).UntrackedFiles()
+
+	require.NoError(t, err)
+	require.Equal(t, []info.ChangeInfo{{Status: info.StatusAdded, Path: "baba.go"}}, changes)
+}
+
+func TestClient_Versions_ReadsFromRevisions(t *testing.T) {
+	runner := NewFakeRunner().
+		MockAt("merge-base main feature", "abc123\n").
+		MockAt("show abc123:./baba/baba.go", "package baba").
+		MockAt("show feature:./baba/baba.go", "package is")
+	client := git.NewClient(runner)
+
+	versions, errVersions := client.Versions(git.Range{Base: "main", Head: "feature", MergeBase: true}, "root")
+	require.NoError(t, errVersions)
+
+	oldSource, errOld := versions.Old("baba/baba.go")
+	require.NoError(t, errOld)
+	require.Equal(t, "package baba", string(oldSource))
+
+	newSource, errNew := versions.New("baba/baba.go")
+	require.NoError(t, errNew)
+	require.Equal(t, "package is", string(newSource))
+}
+
+func TestClient_Versions_Uncommitted_ReadsWorkingTree(t *testing.T) {
+	root := t.TempDir()
+	errWrite := os.WriteFile(filepath.Join(root, "baba.go"), []byte("package you"), os.ModePerm)
+	require.NoError(t, errWrite)
+
+	client := git.NewClient(NewFakeRunner())
+
+	versions, errVersions := client.Versions(git.Range{Base: "main", Uncommitted: true}, root)
+	require.NoError(t, errVersions)
+
+	newSource, errNew := versions.New("baba.go")
+	require.NoError(t, errNew)
+	require.Equal(t, "package you", string(newSource))
+
+	_, errOld := versions.Old("baba.go")
+	require.Error(t, errOld)
+}
+
+func TestClient_Versions_MissingBase_Error(t *testing.T) {
+	client := git.NewClient(NewFakeRunner())
+
+	_, err := client.Versions(git.Range{}, "root")
+
+	require.ErrorIs(t, err, git.ErrMissingBase)
+}
+
+func TestClient_Changes_BelowTopLevel_RelativeToDir(t *testing.T) {
+	top := t.TempDir()
+	dir := filepath.Join(top, "module")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "baba"), os.ModePerm))
+
+	runner := git.CommandRunner{Dir: top}
+	_, errInit := runner.Run("init", "--quiet")
+	require.NoError(t, errInit)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "baba", "baba.go"), []byte("package baba\n"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(top, "outside.go"), []byte("package top\n"), os.ModePerm))
+
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"-c", "user.name=baba", "-c", "user.email=baba@is.you", "commit", "--quiet", "-m", "init"},
+	} {
+		_, errRun := runner.Run(args...)
+		require.NoError(t, errRun)
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "baba", "baba.go"), []byte("package baba\n\nvar is = 1\n"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(top, "outside.go"), []byte("package top\n\nvar is = 1\n"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "you.go"), []byte("package you\n"), os.ModePerm))
+
+	client := git.NewClient(git.CommandRunner{Dir: dir})
+	r := git.Range{Base: "HEAD", Uncommitted: true, Untracked: true}
+
+	changes, errChanges := client.Changes(r)
+	require.NoError(t, errChanges)
+
+	paths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+
+	require.Equal(t, []string{"baba/baba.go", "you.go"}, paths)
+
+	versions, errVersions := client.Versions(r, dir)
+	require.NoError(t, errVersions)
+
+	oldSource, errOld := versions.Old("baba/baba.go")
+	require.NoError(t, errOld)
+	require.Equal(t, "package baba\n", string(oldSource))
+
+	newSource, errNew := versions.New("baba/baba.go")
+	require.NoError(t, errNew)
+	require.Contains(t, string(newSource), "var is")
+}
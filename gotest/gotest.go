@@ -0,0 +1,312 @@
+package gotest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	Tester struct {
+		runner Runner
+		config Config
+	}
+
+	Runner interface {
+		Run(dir string, args ...string) (string, error)
+	}
+
+	CommandRunner struct{}
+
+	Config struct {
+		Parallelism int
+		Flags       []string
+		Timeout     time.Duration
+	}
+
+	Package struct {
+		Name    string
+		Dir     string
+		Pattern string
+		Run     string
+	}
+
+	Summary struct {
+		Packages []PackageResult `json:"packages"`
+		Passed   int             `json:"passed"`
+		Failed   int             `json:"failed"`
+		Skipped  int             `json:"skipped"`
+		Elapsed  float64         `json:"elapsed"`
+	}
+
+	PackageResult struct {
+		Package    string       `json:"package"`
+		ImportPath string       `json:"import_path,omitempty"`
+		Action     string       `json:"action"`
+		Elapsed    float64      `json:"elapsed"`
+		TimedOut   bool         `json:"timed_out,omitempty"`
+		Tests      []TestResult `json:"tests,omitempty"`
+		Output     string       `json:"output,omitempty"`
+	}
+
+	TestResult struct {
+		Name    string  `json:"name"`
+		Action  string  `json:"action"`
+		Elapsed float64 `json:"elapsed"`
+	}
+
+	event struct {
+		Action  string
+		Package string
+		Test    string
+		Elapsed float64
+		Output  string
+	}
+)
+
+const (
+	ActionPass = "pass"
+	ActionFail = "fail"
+	ActionSkip = "skip"
+
+	timeoutPanic = "panic: test timed out after"
+)
+
+var (
+	ErrTestsFailed = errors.New("tests failed")
+)
+
+func NewTester(runner Runner, config Config) Tester {
+	if config.Parallelism < 1 {
+		config.Parallelism = 1
+	}
+
+	return Tester{runner: runner, config: config}
+}
+
+func (t Tester) Test(packages []Package) Summary {
+	started := time.Now()
+	results := make([]PackageResult, len(packages))
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < t.config.Parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for index := range indexes {
+				results[index] = t.testPackage(packages[index])
+			}
+		}()
+	}
+
+	for i := range packages {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	summary := Summary{
+		Packages: results,
+		Elapsed:  time.Since(started).Seconds(),
+	}
+
+	for _, result := range results {
+		switch result.Action {
+		case ActionPass:
+			summary.Passed++
+		case ActionSkip:
+			summary.Skipped++
+		default:
+			summary.Failed++
+		}
+	}
+
+	return summary
+}
+
+func (t Tester) testPackage(pkg Package) PackageResult {
+	args := []string{"test", "-json"}
+	if t.config.Timeout > 0 {
+		args = append(args, "-timeout", t.config.Timeout.String())
+	}
+
+	args = append(args, t.config.Flags...)
+	if pkg.Run != "" {
+		args = append(args, "-run", pkg.Run)
+	}
+
+	out, errRun := t.runner.Run(pkg.Dir, append(args, pkg.Pattern)...)
+
+	result := PackageResult{Package: pkg.Name, Output: out}
+	if parsed := ParseEvents(out); len(parsed) > 0 {
+		result = parsed[0]
+		result.Package = pkg.Name
+	}
+
+	if errRun == nil && result.Action == "" {
+		result.Action = ActionSkip // nothing was reported for the package
+	}
+
+	if errRun != nil && result.Action != ActionFail {
+		result.Action = ActionFail // e.g. the package could not be built
+		result.Output += errRun.Error()
+	}
+
+	if result.Action != ActionFail {
+		result.Output = ""
+	}
+
+	return result
+}
+
+// RunPattern returns the -run regex matching exactly the given top level test functions,
+// or an empty one running every test when there are none.
+func RunPattern(functions []string) string {
+	if len(functions) == 0 {
+		return ""
+	}
+
+	quoted := make([]string, 0, len(functions))
+	for _, function := range functions {
+		quoted = append(quoted, regexp.QuoteMeta(function))
+	}
+
+	return "^(" + strings.Join(quoted, "|") + ")$"
+}
+
+func ParseEvents(out string) []PackageResult {
+	results := make([]PackageResult, 0, 1)
+	packages := make(map[string]int)
+	tests := make(map[string]map[string]int)
+	outputs := make(map[string]*strings.Builder)
+	unparsed := strings.Builder{}
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		e := event{}
+		if errUnmarshal := json.Unmarshal(scanner.Bytes(), &e); errUnmarshal != nil || e.Action == "" {
+			unparsed.WriteString(scanner.Text() + "\n") // build errors are not encoded as events
+			continue
+		}
+
+		if _, ok := packages[e.Package]; ok == false {
+			packages[e.Package] = len(results)
+			tests[e.Package] = make(map[string]int)
+			outputs[e.Package] = &strings.Builder{}
+			results = append(results, PackageResult{Package: e.Package, ImportPath: e.Package})
+		}
+
+		result := &results[packages[e.Package]]
+
+		if e.Action == "output" {
+			outputs[e.Package].WriteString(e.Output)
+			result.TimedOut = result.TimedOut || strings.HasPrefix(e.Output, timeoutPanic)
+			continue
+		}
+
+		if e.Action != ActionPass && e.Action != ActionFail && e.Action != ActionSkip {
+			continue
+		}
+
+		if e.Test == "" {
+			result.Action = e.Action
+			result.Elapsed = e.Elapsed
+			continue
+		}
+
+		index, ok := tests[e.Package][e.Test]
+		if ok == false {
+			index = len(result.Tests)
+			tests[e.Package][e.Test] = index
+			result.Tests = append(result.Tests, TestResult{Name: e.Test})
+		}
+
+		result.Tests[index].Action = e.Action
+		result.Tests[index].Elapsed = e.Elapsed
+	}
+
+	for i := range results {
+		results[i].Output = outputs[results[i].ImportPath].String()
+	}
+
+	if len(results) > 0 {
+		results[0].Output = unparsed.String() + results[0].Output
+	}
+
+	return results
+}
+
+func (s Summary) FailedPackages() []string {
+	result := make([]string, 0, s.Failed)
+
+	for _, pkg := range s.Packages {
+		if pkg.Action == ActionFail {
+			result = append(result, pkg.Package)
+		}
+	}
+
+	return result
+}
+
+func (s Summary) String() string {
+	builder := strings.Builder{}
+
+	for _, pkg := range s.Packages {
+		status := "ok  "
+		switch {
+		case pkg.TimedOut:
+			status = "TIME"
+		case pkg.Action == ActionFail:
+			status = "FAIL"
+		case pkg.Action == ActionSkip:
+			status = "skip"
+		}
+
+		builder.WriteString(fmt.Sprintf("%s %s (%.2fs)\n", status, pkg.Package, pkg.Elapsed))
+		for _, test := range pkg.Tests {
+			if test.Action == ActionFail {
+				builder.WriteString(fmt.Sprintf("     --- FAIL: %s (%.2fs)\n", test.Name, test.Elapsed))
+			}
+		}
+	}
+
+	builder.WriteString(fmt.Sprintf("%d packages: %d passed, %d failed, %d skipped in %.2fs\n",
+		len(s.Packages), s.Passed, s.Failed, s.Skipped, s.Elapsed))
+
+	if failed := s.FailedPackages(); len(failed) > 0 {
+		builder.WriteString("failing packages:\n")
+		for _, pkg := range failed {
+			builder.WriteString("    " + pkg + "\n")
+		}
+	}
+
+	return builder.String()
+}
+
+func (r CommandRunner) Run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if errRun := cmd.Run(); errRun != nil {
+		return stdout.String(), fmt.Errorf("go %s: %w: %s", strings.Join(args, " "), errRun, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,167 @@
+package gotest_test
+
+import (
+	"errors"
+	"github.com/go-lean/bevaluate/gotest"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	errKaboom = errors.New("kaboom")
+)
+
+const (
+	passingEvents = `{"Action":"start","Package":"example.com/baba"}
+{"Action":"run","Package":"example.com/baba","Test":"TestBaba"}
+{"Action":"output","Package":"example.com/baba","Test":"TestBaba","Output":"=== RUN   TestBaba\n"}
+{"Action":"pass","Package":"example.com/baba","Test":"TestBaba","Elapsed":0.5}
+{"Action":"pass","Package":"example.com/baba","Elapsed":0.7}
+`
+	failingEvents = `{"Action":"run","Package":"example.com/is","Test":"TestIs"}
+{"Action":"output","Package":"example.com/is","Test":"TestIs","Output":"    is_test.go:5: boom\n"}
+{"Action":"fail","Package":"example.com/is","Test":"TestIs","Elapsed":0.1}
+{"Action":"fail","Package":"example.com/is","Elapsed":0.2}
+`
+	timedOutEvents = `{"Action":"output","Package":"example.com/you","Output":"panic: test timed out after 1s\n"}
+{"Action":"fail","Package":"example.com/you","Elapsed":1}
+`
+	noTestsEvents = `{"Action":"output","Package":"example.com/flag","Output":"?   \texample.com/flag\t[no test files]\n"}
+{"Action":"skip","Package":"example.com/flag","Elapsed":0}
+`
+)
+
+type (
+	FakeRunner struct {
+		mu      sync.Mutex
+		outputs map[string]string
+		errors  map[string]error
+		calls   []string
+	}
+)
+
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{
+		outputs: make(map[string]string),
+		errors:  make(map[string]error),
+	}
+}
+
+func (r *FakeRunner) MockAt(pattern, output string, err error) *FakeRunner {
+	r.outputs[pattern] = output
+	r.errors[pattern] = err
+	return r
+}
+
+func (r *FakeRunner) Run(dir string, args ...string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pattern := args[len(args)-1]
+	r.calls = append(r.calls, dir+" "+strings.Join(args, " "))
+
+	output, ok := r.outputs[pattern]
+	if ok == false {
+		return "", errKaboom
+	}
+
+	return output, r.errors[pattern]
+}
+
+func TestTester_Test_Nil_Empty(t *testing.T) {
+	tester := gotest.NewTester(NewFakeRunner(), gotest.Config{})
+
+	summary := tester.Test(nil)
+
+	require.Empty(t, summary.Packages)
+	require.Zero(t, summary.Failed)
+}
+
+func TestTester_Test_PassesFlagsAndTimeout(t *testing.T) {
+	runner := NewFakeRunner().MockAt("./baba", passingEvents, nil)
+	tester := gotest.NewTester(runner, gotest.Config{Flags: []string{"-race"}, Timeout: time.Minute})
+
+	tester.Test([]gotest.Package{{Name: "baba", Dir: "root", Pattern: "./baba"}})
+
+	require.Equal(t, []string{"root test -json -timeout 1m0s -race ./baba"}, runner.calls)
+}
+
+func TestTester_Test_AggregatesResults(t *testing.T) {
+	runner := NewFakeRunner().
+		MockAt("./baba", passingEvents, nil).
+		MockAt("./is", failingEvents, errKaboom).
+		MockAt("./you", timedOutEvents, errKaboom).
+		MockAt("./flag", noTestsEvents, nil)
+	tester := gotest.NewTester(runner, gotest.Config{Parallelism: 3})
+
+	summary := tester.Test([]gotest.Package{
+		{Name: "baba", Pattern: "./baba"},
+		{Name: "is", Pattern: "./is"},
+		{Name: "you", Pattern: "./you"},
+		{Name: "flag", Pattern: "./flag"},
+	})
+
+	require.Equal(t, 1, summary.Passed)
+	require.Equal(t, 2, summary.Failed)
+	require.Equal(t, 1, summary.Skipped)
+	require.Equal(t, []string{"is", "you"}, summary.FailedPackages())
+
+	baba := summary.Packages[0]
+	require.Equal(t, gotest.ActionPass, baba.Action)
+	require.Equal(t, 0.7, baba.Elapsed)
+	require.Equal(t, []gotest.TestResult{{Name: "TestBaba", Action: gotest.ActionPass, Elapsed: 0.5}}, baba.Tests)
+	require.Empty(t, baba.Output)
+
+	is := summary.Packages[1]
+	require.Equal(t, gotest.ActionFail, is.Action)
+	require.Contains(t, is.Output, "boom")
+	require.False(t, is.TimedOut)
+
+	require.True(t, summary.Packages[2].TimedOut)
+}
+
+func TestTester_Test_BuildFailure_Fails(t *testing.T) {
+	runner := NewFakeRunner().MockAt("./baba", "", errKaboom)
+	tester := gotest.NewTester(runner, gotest.Config{})
+
+	summary := tester.Test([]gotest.Package{{Name: "baba", Pattern: "./baba"}})
+
+	require.Equal(t, 1, summary.Failed)
+	require.Equal(t, gotest.ActionFail, summary.Packages[0].Action)
+	require.Contains(t, summary.Packages[0].Output, "kaboom")
+}
+
+func TestSummary_String_ListsFailingPackages(t *testing.T) {
+	summary := gotest.Summary{
+		Packages: []gotest.PackageResult{
+			{Package: "baba", Action: gotest.ActionPass},
+			{Package: "is", Action: gotest.ActionFail, Tests: []gotest.TestResult{{Name: "TestIs", Action: gotest.ActionFail}}},
+		},
+		Passed: 1,
+		Failed: 1,
+	}
+
+	text := summary.String()
+
+	require.Contains(t, text, "ok   baba")
+	require.Contains(t, text, "FAIL is")
+	require.Contains(t, text, "--- FAIL: TestIs")
+	require.Contains(t, text, "2 packages: 1 passed, 1 failed, 0 skipped")
+	require.True(t, strings.HasSuffix(text, "failing packages:\n    is\n"))
+}
+
+func TestTester_Test_PassesRunPattern(t *testing.T) {
+	runner := NewFakeRunner().MockAt("./baba", passingEvents, nil)
+	tester := gotest.NewTester(runner, gotest.Config{})
+
+	tester.Test([]gotest.Package{{Name: "baba", Dir: "root", Pattern: "./baba", Run: gotest.RunPattern([]string{"TestBaba", "TestIs"})}})
+
+	require.Equal(t, []string{"root test -json -run ^(TestBaba|TestIs)$ ./baba"}, runner.calls)
+}
+
+func TestRunPattern_Empty_RunsEverything(t *testing.T) {
+	require.Empty(t, gotest.RunPattern(nil))
+}
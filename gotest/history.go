@@ -0,0 +1,76 @@
+package gotest
+
+type (
+	History struct {
+		Packages map[string]PackageHistory `json:"packages"`
+	}
+
+	PackageHistory struct {
+		Duration float64 `json:"duration"`
+		Runs     int     `json:"runs"`
+	}
+)
+
+const (
+	// historyWeight is the weight of the latest duration, so that slow runs do not stick forever
+	historyWeight = 0.5
+)
+
+func NewHistory() History {
+	return History{Packages: make(map[string]PackageHistory)}
+}
+
+func (h History) Record(results []PackageResult) History {
+	packages := make(map[string]PackageHistory, len(h.Packages)+len(results))
+	for importPath, pkg := range h.Packages {
+		packages[importPath] = pkg
+	}
+
+	for _, result := range results {
+		if result.ImportPath == "" || result.Action == ActionSkip {
+			continue
+		}
+
+		pkg, ok := packages[result.ImportPath]
+		if ok == false {
+			pkg.Duration = result.Elapsed
+		} else {
+			pkg.Duration = historyWeight*result.Elapsed + (1-historyWeight)*pkg.Duration
+		}
+
+		pkg.Runs++
+		packages[result.ImportPath] = pkg
+	}
+
+	return History{Packages: packages}
+}
+
+// Durations estimates the duration of every package, falling back to its size for packages
+// without any history, scaled by the average duration per size of the packages with history.
+func (h History) Durations(sizes map[string]int) map[string]float64 {
+	result := make(map[string]float64, len(sizes))
+	totalDuration, totalSize := 0.0, 0
+
+	for importPath, size := range sizes {
+		if pkg, ok := h.Packages[importPath]; ok {
+			totalDuration += pkg.Duration
+			totalSize += size
+		}
+	}
+
+	perSize := 1.0
+	if totalSize > 0 && totalDuration > 0 {
+		perSize = totalDuration / float64(totalSize)
+	}
+
+	for importPath, size := range sizes {
+		if pkg, ok := h.Packages[importPath]; ok {
+			result[importPath] = pkg.Duration
+			continue
+		}
+
+		result[importPath] = float64(size) * perSize
+	}
+
+	return result
+}
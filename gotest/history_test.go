@@ -0,0 +1,38 @@
+package gotest_test
+
+import (
+	"github.com/go-lean/bevaluate/gotest"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestHistory_Record_AveragesDurations(t *testing.T) {
+	history := gotest.NewHistory().Record([]gotest.PackageResult{
+		{ImportPath: "example.com/baba", Action: gotest.ActionPass, Elapsed: 4},
+		{ImportPath: "example.com/is", Action: gotest.ActionSkip, Elapsed: 1},
+	})
+
+	history = history.Record([]gotest.PackageResult{
+		{ImportPath: "example.com/baba", Action: gotest.ActionFail, Elapsed: 2},
+	})
+
+	require.Equal(t, map[string]gotest.PackageHistory{
+		"example.com/baba": {Duration: 3, Runs: 2},
+	}, history.Packages)
+}
+
+func TestHistory_Durations_FallsBackToScaledSize(t *testing.T) {
+	history := gotest.History{Packages: map[string]gotest.PackageHistory{
+		"example.com/baba": {Duration: 6, Runs: 1},
+	}}
+
+	durations := history.Durations(map[string]int{"example.com/baba": 3, "example.com/is": 2})
+
+	require.Equal(t, map[string]float64{"example.com/baba": 6, "example.com/is": 4}, durations)
+}
+
+func TestHistory_Durations_NoHistory_UsesSize(t *testing.T) {
+	durations := gotest.NewHistory().Durations(map[string]int{"example.com/baba": 3})
+
+	require.Equal(t, map[string]float64{"example.com/baba": 3}, durations)
+}
@@ -0,0 +1,90 @@
+package gotest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	Shard struct {
+		Index int
+		Count int
+	}
+
+	weighted struct {
+		index  int
+		weight float64
+	}
+)
+
+var (
+	ErrInvalidShard = errors.New("invalid shard, expected <index>/<count> with 1 <= index <= count")
+)
+
+func ParseShard(value string) (Shard, error) {
+	index, count, ok := strings.Cut(value, "/")
+	if ok == false {
+		return Shard{}, fmt.Errorf("%w: %q", ErrInvalidShard, value)
+	}
+
+	shard := Shard{}
+	var errIndex, errCount error
+	shard.Index, errIndex = strconv.Atoi(index)
+	shard.Count, errCount = strconv.Atoi(count)
+
+	if errIndex != nil || errCount != nil || shard.Index < 1 || shard.Index > shard.Count {
+		return Shard{}, fmt.Errorf("%w: %q", ErrInvalidShard, value)
+	}
+
+	return shard, nil
+}
+
+func (s Shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Count)
+}
+
+// Select returns the items of the shard, assigning the heaviest items first to the lightest shard.
+// The assignment only depends on the items and their weights, so every worker computes the same one.
+func (s Shard) Select(items []string, weights map[string]float64) []string {
+	sorted := make([]weighted, 0, len(items))
+	for i, item := range items {
+		sorted = append(sorted, weighted{index: i, weight: weights[item]})
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].weight != sorted[j].weight {
+			return sorted[i].weight > sorted[j].weight
+		}
+
+		return items[sorted[i].index] < items[sorted[j].index]
+	})
+
+	totals := make([]float64, s.Count)
+	counts := make([]int, s.Count)
+	selected := make([]bool, len(items))
+
+	for _, item := range sorted {
+		lightest := 0
+		for shard, total := range totals {
+			if total < totals[lightest] || total == totals[lightest] && counts[shard] < counts[lightest] {
+				lightest = shard
+			}
+		}
+
+		totals[lightest] += item.weight
+		counts[lightest]++
+		selected[item.index] = lightest == s.Index-1
+	}
+
+	result := make([]string, 0, len(items)/s.Count+1)
+	for i, item := range items {
+		if selected[i] {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,48 @@
+package gotest_test
+
+import (
+	"github.com/go-lean/bevaluate/gotest"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseShard_Valid(t *testing.T) {
+	shard, err := gotest.ParseShard("2/5")
+
+	require.NoError(t, err)
+	require.Equal(t, gotest.Shard{Index: 2, Count: 5}, shard)
+	require.Equal(t, "2/5", shard.String())
+}
+
+func TestParseShard_Invalid_Error(t *testing.T) {
+	for _, value := range []string{"", "2", "0/5", "6/5", "a/5", "2/b"} {
+		_, err := gotest.ParseShard(value)
+
+		require.ErrorIs(t, err, gotest.ErrInvalidShard, value)
+	}
+}
+
+func TestShard_Select_BalancesByWeight(t *testing.T) {
+	items := []string{"baba", "is", "you", "flag", "win"}
+	weights := map[string]float64{"baba": 10, "is": 6, "you": 5, "flag": 3, "win": 1}
+
+	first := gotest.Shard{Index: 1, Count: 2}.Select(items, weights)
+	second := gotest.Shard{Index: 2, Count: 2}.Select(items, weights)
+
+	require.Equal(t, []string{"baba", "flag"}, first)
+	require.Equal(t, []string{"is", "you", "win"}, second)
+}
+
+func TestShard_Select_NoWeights_SplitsEvenly(t *testing.T) {
+	items := []string{"baba", "is", "you", "flag"}
+	selected := make([]string, 0, len(items))
+
+	for i := 1; i <= 2; i++ {
+		shard := gotest.Shard{Index: i, Count: 2}.Select(items, nil)
+
+		require.Len(t, shard, 2)
+		selected = append(selected, shard...)
+	}
+
+	require.ElementsMatch(t, items, selected)
+}
@@ -13,28 +13,66 @@ var (
 func ParseGitChanges(changesContent string) ([]ChangeInfo, error) {
 	lines := strings.Split(changesContent, "\n")
 	result := make([]ChangeInfo, 0, len(lines))
+
 	for _, line := range lines {
 		if line == "" {
 			break
 		}
 
-		if isValidChange(line) == false {
+		change, ok := parseChange(line)
+		if ok == false {
 			return nil, errInvalidChangesFormat
 		}
 
-		path := strings.Trim(line[2:], " ")
-		if path == "" {
-			return nil, errInvalidChangesFormat
-		}
-		result = append(result, ChangeInfo{
-			Path:      path,
-			IsDeleted: line[0] == 'D',
-		})
+		result = append(result, change)
 	}
 
 	return result, nil
 }
 
-func isValidChange(line string) bool {
-	return len(line) >= 3 && unicode.IsDigit(rune(line[0])) == false && line[1] == '\t'
+func parseChange(line string) (ChangeInfo, bool) {
+	fields := strings.Split(line, "\t")
+	if len(fields) < 2 || isValidStatus(fields[0]) == false {
+		return ChangeInfo{}, false
+	}
+
+	paths := fields[1:]
+	for i, path := range paths {
+		paths[i] = strings.Trim(path, " ")
+		if paths[i] == "" {
+			return ChangeInfo{}, false
+		}
+	}
+
+	change := ChangeInfo{Status: ChangeStatus(fields[0][0])}
+	if change.HasOldPath() {
+		if len(paths) != 2 {
+			return ChangeInfo{}, false
+		}
+
+		change.OldPath = paths[0]
+		change.Path = paths[1]
+		return change, true
+	}
+
+	if len(paths) != 1 {
+		return ChangeInfo{}, false
+	}
+
+	change.Path = paths[0]
+	return change, true
+}
+
+func isValidStatus(status string) bool {
+	if status == "" || unicode.IsUpper(rune(status[0])) == false {
+		return false
+	}
+
+	for _, r := range status[1:] {
+		if unicode.IsDigit(r) == false {
+			return false
+		}
+	}
+
+	return true
 }
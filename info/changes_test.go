@@ -19,6 +19,18 @@ func TestParseGitChanges_BadContent_Error(t *testing.T) {
 			name:    "empty path content should be invalid",
 			content: "D\t ",
 		},
+		{
+			name:    "digit status should be invalid",
+			content: "1\tbaba.go",
+		},
+		{
+			name:    "rename without new path should be invalid",
+			content: "R100\tbaba.go",
+		},
+		{
+			name:    "modification with two paths should be invalid",
+			content: "M\tbaba.go\tis.go",
+		},
 	}
 
 	for _, c := range testCases {
@@ -36,7 +48,7 @@ func TestParseGitChanges_OK(t *testing.T) {
 	changes, errParse := info.ParseGitChanges("D\tbaba.go\nM\tflag.go")
 	require.NoError(t, errParse)
 	require.Len(t, changes, 2)
-	expected := []info.ChangeInfo{{Path: "baba.go", IsDeleted: true}, {Path: "flag.go"}}
+	expected := []info.ChangeInfo{{Path: "baba.go", Status: info.StatusDeleted}, {Path: "flag.go", Status: info.StatusModified}}
 	require.ElementsMatch(t, expected, changes)
 }
 
@@ -44,7 +56,7 @@ func TestParseGitChanges_EndingWithNewLine_OK(t *testing.T) {
 	changes, errParse := info.ParseGitChanges("D\tbaba.go\nM\tflag.go\n")
 	require.NoError(t, errParse)
 	require.Len(t, changes, 2)
-	expected := []info.ChangeInfo{{Path: "baba.go", IsDeleted: true}, {Path: "flag.go"}}
+	expected := []info.ChangeInfo{{Path: "baba.go", Status: info.StatusDeleted}, {Path: "flag.go", Status: info.StatusModified}}
 	require.ElementsMatch(t, expected, changes)
 }
 
@@ -53,3 +65,13 @@ func TestParseGitChanges_EmptyContent_OK(t *testing.T) {
 	require.NoError(t, errParse)
 	require.Empty(t, changes)
 }
+
+func TestParseGitChanges_RenamedAndCopied_OK(t *testing.T) {
+	changes, errParse := info.ParseGitChanges("R100\told/baba.go\tnew/baba.go\nC75\tbaba.go\tis.go\n")
+	require.NoError(t, errParse)
+	expected := []info.ChangeInfo{
+		{Path: "new/baba.go", OldPath: "old/baba.go", Status: info.StatusRenamed},
+		{Path: "is.go", OldPath: "baba.go", Status: info.StatusCopied},
+	}
+	require.Equal(t, expected, changes)
+}
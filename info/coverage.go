@@ -0,0 +1,190 @@
+package info
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	CoverageIndex struct {
+		Packages map[string]map[string][]LineRange `json:"packages"`
+	}
+
+	LineRange struct {
+		Start int `json:"start"`
+		End   int `json:"end"`
+	}
+)
+
+const (
+	coverModePrefix = "mode:"
+)
+
+var (
+	ErrInvalidCoverProfile = errors.New("invalid cover profile")
+)
+
+func NewCoverageIndex() CoverageIndex {
+	return CoverageIndex{Packages: make(map[string]map[string][]LineRange)}
+}
+
+// ParseCoverProfile reads the executed lines of every file of a go test -coverprofile output,
+// keyed by the file path relative to the root of the modules.
+func ParseCoverProfile(profile string, modules []Module) (map[string][]LineRange, error) {
+	result := make(map[string][]LineRange)
+	scanner := bufio.NewScanner(strings.NewReader(profile))
+
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, coverModePrefix) {
+			continue
+		}
+
+		file, lines, count, errLine := parseCoverLine(line)
+		if errLine != nil {
+			return nil, fmt.Errorf("%w at line %d: %v", ErrInvalidCoverProfile, number, errLine)
+		}
+
+		if count == 0 {
+			continue
+		}
+
+		path, ok := resolveImport(file, modules)
+		if ok == false {
+			continue // not part of the evaluated modules
+		}
+
+		result[path] = append(result[path], lines)
+	}
+
+	for path, lines := range result {
+		result[path] = mergeLineRanges(lines)
+	}
+
+	return result, nil
+}
+
+func (c CoverageIndex) Record(testPkg string, coverage map[string][]LineRange) CoverageIndex {
+	packages := make(map[string]map[string][]LineRange, len(c.Packages)+1)
+	for pkg, files := range c.Packages {
+		packages[pkg] = files
+	}
+
+	packages[testPkg] = coverage // the previous coverage of the package is outdated
+	return CoverageIndex{Packages: packages}
+}
+
+// TestsCovering returns the test packages whose recorded coverage touches the changed lines
+// of the previous version of the file. It is only ok when every changed hunk is covered by some
+// package, since an uncovered one, like a changed type or constant declaration, could affect tests
+// which never executed the other hunks. The profiles have to be recorded with -coverpkg, otherwise
+// only the tests inside the package of the file are known to cover it. Renamed files are evaluated
+// as a deletion and a modification without hunks, so they never get here with their old path.
+func (c CoverageIndex) TestsCovering(change ChangeInfo) ([]string, bool) {
+	selected := make(map[string]struct{})
+	for _, changed := range changedHunks(change) {
+		covered := false
+		for pkg, files := range c.Packages {
+			if overlaps(files[change.Path], changed) {
+				selected[pkg] = struct{}{}
+				covered = true
+			}
+		}
+
+		if covered == false {
+			return nil, false
+		}
+	}
+
+	result := make([]string, 0, len(selected))
+	for pkg := range selected {
+		result = append(result, pkg)
+	}
+
+	sort.Strings(result)
+	return result, len(result) > 0
+}
+
+func parseCoverLine(line string) (string, LineRange, int, error) {
+	file, rest, ok := strings.Cut(line, ":")
+	fields := strings.Fields(rest)
+	if ok == false || len(fields) != 3 {
+		return "", LineRange{}, 0, errors.New("expected file:start.col,end.col statements count")
+	}
+
+	start, end, ok := strings.Cut(fields[0], ",")
+	if ok == false {
+		return "", LineRange{}, 0, errors.New("missing block end")
+	}
+
+	startLine, errStart := strconv.Atoi(strings.Split(start, ".")[0])
+	endLine, errEnd := strconv.Atoi(strings.Split(end, ".")[0])
+	count, errCount := strconv.Atoi(fields[2])
+	if errStart != nil || errEnd != nil || errCount != nil {
+		return "", LineRange{}, 0, errors.New("invalid numbers")
+	}
+
+	return file, LineRange{Start: startLine, End: endLine}, count, nil
+}
+
+// changedHunks returns the changed lines of every hunk, or a single nil range when the hunks are
+// unknown and the whole file is considered to be changed.
+func changedHunks(change ChangeInfo) [][]LineRange {
+	if len(change.Hunks) == 0 {
+		return [][]LineRange{nil}
+	}
+
+	result := make([][]LineRange, 0, len(change.Hunks))
+	for _, hunk := range change.Hunks {
+		end := hunk.OldStart + hunk.OldLines - 1
+		if hunk.OldLines == 0 {
+			end = hunk.OldStart + 1 // lines were only added after the start, so its neighbours are affected
+		}
+
+		result = append(result, []LineRange{{Start: hunk.OldStart, End: end}})
+	}
+
+	return result
+}
+
+func overlaps(covered, changed []LineRange) bool {
+	if changed == nil {
+		return len(covered) > 0
+	}
+
+	for _, c := range covered {
+		for _, ch := range changed {
+			if c.Start <= ch.End && ch.Start <= c.End {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func mergeLineRanges(lines []LineRange) []LineRange {
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].Start < lines[j].Start
+	})
+
+	result := make([]LineRange, 0, len(lines))
+	for _, line := range lines {
+		last := len(result) - 1
+		if last >= 0 && line.Start <= result[last].End+1 {
+			if line.End > result[last].End {
+				result[last].End = line.End
+			}
+
+			continue
+		}
+
+		result = append(result, line)
+	}
+
+	return result
+}
@@ -0,0 +1,102 @@
+package info_test
+
+import (
+	"github.com/go-lean/bevaluate/info"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const coverProfile = `mode: set
+github.com/baba/is/you/baba/baba.go:3.20,5.2 1 1
+github.com/baba/is/you/baba/baba.go:5.2,6.10 1 1
+github.com/baba/is/you/baba/baba.go:9.20,11.2 1 0
+github.com/baba/is/you/tools/lint/lint.go:3.14,4.2 1 1
+github.com/other/module/other.go:3.14,4.2 1 1
+`
+
+func TestParseCoverProfile_MergesExecutedBlocks(t *testing.T) {
+	modules := []info.Module{
+		{Path: "github.com/baba/is/you", Dir: "."},
+		{Path: "github.com/baba/is/you/tools", Dir: "tools"},
+	}
+
+	coverage, err := info.ParseCoverProfile(coverProfile, modules)
+
+	require.NoError(t, err)
+	require.Equal(t, map[string][]info.LineRange{
+		"baba/baba.go":       {{Start: 3, End: 6}},
+		"tools/lint/lint.go": {{Start: 3, End: 4}},
+	}, coverage)
+}
+
+func TestParseCoverProfile_InvalidLine_Error(t *testing.T) {
+	_, err := info.ParseCoverProfile("mode: set\nbaba.go 3.20,5.2 1 1\n", nil)
+
+	require.ErrorIs(t, err, info.ErrInvalidCoverProfile)
+	require.Contains(t, err.Error(), "line 2")
+}
+
+func TestCoverageIndex_TestsCovering(t *testing.T) {
+	index := info.NewCoverageIndex().
+		Record("baba", map[string][]info.LineRange{"baba/baba.go": {{Start: 3, End: 6}}}).
+		Record("cmd/baba", map[string][]info.LineRange{"baba/baba.go": {{Start: 3, End: 4}, {Start: 20, End: 30}}})
+
+	testCases := []struct {
+		name     string
+		change   info.ChangeInfo
+		expected []string
+		ok       bool
+	}{
+		{
+			name:     "changed file without lines should select every covering package",
+			change:   info.ChangeInfo{Path: "baba/baba.go"},
+			expected: []string{"baba", "cmd/baba"},
+			ok:       true,
+		},
+		{
+			name:     "changed lines should only select packages covering them",
+			change:   info.ChangeInfo{Path: "baba/baba.go", Hunks: []info.Hunk{{OldStart: 25, OldLines: 2}}},
+			expected: []string{"cmd/baba"},
+			ok:       true,
+		},
+		{
+			name:     "added lines should select packages covering their neighbours",
+			change:   info.ChangeInfo{Path: "baba/baba.go", Hunks: []info.Hunk{{OldStart: 6, OldLines: 0}}},
+			expected: []string{"baba"},
+			ok:       true,
+		},
+		{
+			name: "hunks covered by different packages should select all of them",
+			change: info.ChangeInfo{Path: "baba/baba.go", Hunks: []info.Hunk{
+				{OldStart: 5, OldLines: 1},
+				{OldStart: 25, OldLines: 2},
+			}},
+			expected: []string{"baba", "cmd/baba"},
+			ok:       true,
+		},
+		{
+			name:   "uncovered lines should not be ok",
+			change: info.ChangeInfo{Path: "baba/baba.go", Hunks: []info.Hunk{{OldStart: 10, OldLines: 3}}},
+		},
+		{
+			name: "any uncovered hunk should not be ok",
+			change: info.ChangeInfo{Path: "baba/baba.go", Hunks: []info.Hunk{
+				{OldStart: 25, OldLines: 2},
+				{OldStart: 10, OldLines: 3},
+			}},
+		},
+		{
+			name:   "uncovered file should not be ok",
+			change: info.ChangeInfo{Path: "is/is.go"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tests, ok := index.TestsCovering(tc.change)
+
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.expected, tests)
+		})
+	}
+}
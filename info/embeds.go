@@ -0,0 +1,116 @@
+package info
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	embedDirective = "//go:embed"
+	embedAllPrefix = "all:"
+)
+
+func (p PackageInfo) EmbedsFile(filePath string) (bool, bool) {
+	for _, pattern := range p.Embeds {
+		if matchesEmbed(p.Path, pattern, filePath) {
+			return true, false
+		}
+	}
+
+	for _, pattern := range p.TestEmbeds {
+		if matchesEmbed(p.Path, pattern, filePath) {
+			return true, true
+		}
+	}
+
+	return false, false
+}
+
+func readEmbedPatterns(filePath string, source []byte) ([]string, error) {
+	parsedFile, errParse := parser.ParseFile(token.NewFileSet(), filePath, source, parser.ParseComments|parser.SkipObjectResolution)
+	if errParse != nil {
+		return nil, fmt.Errorf("could not parse source file: %w", errParse)
+	}
+
+	result := make([]string, 0)
+	for _, group := range parsedFile.Comments {
+		for _, comment := range group.List {
+			args, ok := strings.CutPrefix(comment.Text, embedDirective)
+			if ok == false || args != "" && args[0] != ' ' && args[0] != '\t' {
+				continue
+			}
+
+			patterns, errPatterns := splitEmbedPatterns(args)
+			if errPatterns != nil {
+				return nil, fmt.Errorf("invalid embed directive in %q: %w", filePath, errPatterns)
+			}
+
+			result = append(result, patterns...)
+		}
+	}
+
+	return result, nil
+}
+
+func splitEmbedPatterns(args string) ([]string, error) {
+	result := make([]string, 0)
+
+	for args = strings.TrimSpace(args); args != ""; args = strings.TrimSpace(args) {
+		if args[0] != '"' && args[0] != '`' {
+			end := strings.IndexAny(args, " \t")
+			if end < 0 {
+				end = len(args)
+			}
+
+			result = append(result, args[:end])
+			args = args[end:]
+			continue
+		}
+
+		quoted, errQuoted := strconv.QuotedPrefix(args)
+		if errQuoted != nil {
+			return nil, errQuoted
+		}
+
+		pattern, _ := strconv.Unquote(quoted)
+		result = append(result, pattern)
+		args = args[len(quoted):]
+	}
+
+	return result, nil
+}
+
+func matchesEmbed(pkgDir, pattern, filePath string) bool {
+	pattern, all := strings.CutPrefix(pattern, embedAllPrefix)
+	rel, errRel := filepath.Rel(pkgDir, filePath)
+	if errRel != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+
+	elements := strings.Split(filepath.ToSlash(rel), "/")
+	for i := range elements {
+		matched, errMatch := path.Match(pattern, strings.Join(elements[:i+1], "/"))
+		if errMatch != nil || matched == false {
+			continue
+		}
+
+		if all {
+			return true
+		}
+
+		for _, element := range elements[i+1:] {
+			if strings.HasPrefix(element, ".") || strings.HasPrefix(element, "_") {
+				return false // hidden files of embedded directories are only included with the all: prefix
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
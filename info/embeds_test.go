@@ -0,0 +1,81 @@
+package info_test
+
+import (
+	"github.com/go-lean/bevaluate/info"
+	"github.com/go-lean/bevaluate/models"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPackageReader_ReadRecursively_EmbedPatterns(t *testing.T) {
+	dirReader := NewDirReader()
+	dirReader.MockAt("baba", []models.DirEntry{DirEntry{name: "web", isDir: true}})
+	dirReader.MockAt("baba/web", []models.DirEntry{
+		DirEntry{name: "web.go"},
+		DirEntry{name: "web_test.go"},
+	})
+
+	opener := NewFileOpener()
+	opener.MockAt("baba/web/web.go", NewFakeFile(`package web
+
+import "embed"
+
+//go:embed templates/*.html "static files" all:assets
+var files embed.FS
+
+//go:embedded is not a directive
+var other string
+`))
+	opener.MockAt("baba/web/web_test.go", NewFakeFile(`package web
+
+import (
+	_ "embed"
+	"testing"
+)
+
+//go:embed testdata/golden.txt
+var golden string
+`))
+
+	r := info.NewPackageReader(dirReader, opener, emptyConfig)
+
+	packages, errRead := r.ReadRecursively("baba", testModuleName)
+
+	require.NoError(t, errRead)
+	require.Len(t, packages, 1)
+
+	require.Equal(t, []string{"templates/*.html", "static files", "all:assets"}, packages[0].Embeds)
+	require.Equal(t, []string{"testdata/golden.txt"}, packages[0].TestEmbeds)
+}
+
+func TestPackageInfo_EmbedsFile(t *testing.T) {
+	pkg := info.PackageInfo{
+		Path:       "web",
+		Embeds:     []string{"templates/*.html", "static", "all:assets"},
+		TestEmbeds: []string{"testdata/golden.txt"},
+	}
+
+	testCases := []struct {
+		path             string
+		expectedEmbedded bool
+		expectedTestOnly bool
+	}{
+		{path: "web/templates/index.html", expectedEmbedded: true},
+		{path: "web/templates/index.css"},
+		{path: "web/static/css/main.css", expectedEmbedded: true},
+		{path: "web/static/.hidden"},
+		{path: "web/static/_partials/nav.html"},
+		{path: "web/assets/.well-known/security.txt", expectedEmbedded: true},
+		{path: "web/testdata/golden.txt", expectedEmbedded: true, expectedTestOnly: true},
+		{path: "other/static/main.css"},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.path, func(t *testing.T) {
+			embedded, testOnly := pkg.EmbedsFile(c.path)
+
+			require.Equal(t, c.expectedEmbedded, embedded)
+			require.Equal(t, c.expectedTestOnly, testOnly)
+		})
+	}
+}
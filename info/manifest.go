@@ -0,0 +1,73 @@
+package info
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-lean/bevaluate/storage"
+	"gopkg.in/yaml.v3"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+type (
+	DeploymentManifest struct {
+		Inputs      []string `json:"inputs,omitempty" yaml:"inputs,omitempty,flow"`
+		Environment string   `json:"environment,omitempty" yaml:"environment,omitempty"`
+		Team        string   `json:"team,omitempty" yaml:"team,omitempty"`
+		Image       string   `json:"image,omitempty" yaml:"image,omitempty"`
+	}
+)
+
+const (
+	ManifestFile = "bevaluate.deploy.yaml"
+
+	rootInputPrefix = "/"
+)
+
+func readDeploymentManifest(path string, opener FileOpener) (*DeploymentManifest, error) {
+	content, errRead := storage.ReadText(path, opener)
+	if errRead != nil {
+		return nil, fmt.Errorf("could not read deployment manifest: %w", errRead)
+	}
+
+	manifest := DeploymentManifest{}
+	decoder := yaml.NewDecoder(strings.NewReader(content))
+	decoder.KnownFields(true)
+
+	if errDecode := decoder.Decode(&manifest); errDecode != nil && errors.Is(errDecode, io.EOF) == false {
+		return nil, fmt.Errorf("could not parse deployment manifest %q: %w", path, errDecode)
+	}
+
+	return &manifest, nil
+}
+
+// IsDeploymentInput reports whether the path is the manifest of the package or one of its declared inputs.
+// Inputs are relative to the package directory, or to the root when starting with a slash, and match
+// the file itself, every file inside a directory or any file matching a glob.
+func (p PackageInfo) IsDeploymentInput(path string) bool {
+	if p.Manifest == nil {
+		return false
+	}
+
+	if path == filepath.Join(p.Path, ManifestFile) {
+		return true
+	}
+
+	for _, input := range p.Manifest.Inputs {
+		resolved := filepath.Join(p.Path, input)
+		if strings.HasPrefix(input, rootInputPrefix) {
+			resolved = filepath.Clean(strings.TrimPrefix(input, rootInputPrefix))
+		}
+
+		if path == resolved || strings.HasPrefix(path, resolved+"/") {
+			return true
+		}
+
+		if matched, _ := filepath.Match(resolved, path); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,94 @@
+package info_test
+
+import (
+	"github.com/go-lean/bevaluate/info"
+	"github.com/go-lean/bevaluate/models"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestPackageReader_ReadRecursively_DeploymentManifest(t *testing.T) {
+	dirReader := NewDirReader()
+	dirReader.MockAt("baba", []models.DirEntry{
+		DirEntry{
+			name:  "cmd",
+			isDir: true,
+		},
+	})
+	dirReader.MockAt("baba/cmd", []models.DirEntry{
+		DirEntry{
+			name: "main.go",
+		},
+		DirEntry{
+			name: info.ManifestFile,
+		},
+	})
+
+	manifest := `inputs: [Dockerfile, /deploy/k8s/]
+environment: production
+team: baba
+image: registry.example.com/baba
+`
+	opener := NewFileOpener()
+	opener.MockAt("baba/cmd/main.go", NewFakeFile("package main").CanClose(false))
+	opener.MockAt("baba/cmd/"+info.ManifestFile, NewFakeFile(manifest).CanClose(true))
+
+	r := info.NewPackageReader(dirReader, opener, emptyConfig)
+
+	packages, errRead := r.ReadRecursively("baba", testModuleName)
+
+	require.NoError(t, errRead)
+	require.Len(t, packages, 1)
+	require.Equal(t, &info.DeploymentManifest{
+		Inputs:      []string{"Dockerfile", "/deploy/k8s/"},
+		Environment: "production",
+		Team:        "baba",
+		Image:       "registry.example.com/baba",
+	}, packages[0].Manifest)
+}
+
+func TestPackageReader_ReadRecursively_UnknownManifestField_Error(t *testing.T) {
+	dirReader := NewDirReader()
+	dirReader.MockAt("baba", []models.DirEntry{
+		DirEntry{
+			name:  "cmd",
+			isDir: true,
+		},
+	})
+	dirReader.MockAt("baba/cmd", []models.DirEntry{
+		DirEntry{
+			name: "main.go",
+		},
+		DirEntry{
+			name: info.ManifestFile,
+		},
+	})
+
+	opener := NewFileOpener()
+	opener.MockAt("baba/cmd/main.go", NewFakeFile("package main").CanClose(false))
+	opener.MockAt("baba/cmd/"+info.ManifestFile, NewFakeFile("imgae: baba").CanClose(true))
+
+	r := info.NewPackageReader(dirReader, opener, emptyConfig)
+
+	_, errRead := r.ReadRecursively("baba", testModuleName)
+
+	require.ErrorContains(t, errRead, "could not parse deployment manifest")
+}
+
+func TestPackageInfo_IsDeploymentInput(t *testing.T) {
+	pkg := info.PackageInfo{
+		Path: "cmd/baba",
+		Manifest: &info.DeploymentManifest{
+			Inputs: []string{"Dockerfile", "../../configs/shared.yaml", "/deploy/k8s/baba", "/deploy/*.env"},
+		},
+	}
+
+	require.True(t, pkg.IsDeploymentInput("cmd/baba/"+info.ManifestFile))
+	require.True(t, pkg.IsDeploymentInput("cmd/baba/Dockerfile"))
+	require.True(t, pkg.IsDeploymentInput("configs/shared.yaml"))
+	require.True(t, pkg.IsDeploymentInput("deploy/k8s/baba/deployment.yaml"))
+	require.True(t, pkg.IsDeploymentInput("deploy/prod.env"))
+	require.False(t, pkg.IsDeploymentInput("deploy/k8s/babatwo/deployment.yaml"))
+	require.False(t, pkg.IsDeploymentInput("cmd/is/Dockerfile"))
+	require.False(t, info.PackageInfo{Path: "cmd/baba"}.IsDeploymentInput("cmd/baba/"+info.ManifestFile))
+}
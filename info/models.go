@@ -1,17 +1,56 @@
 package info
 
-import "regexp"
+import (
+	"path/filepath"
+	"regexp"
+)
 
 type (
 	ChangeInfo struct {
-		Path      string
-		IsDeleted bool
+		Status  ChangeStatus
+		Path    string
+		OldPath string
+		Hunks   []Hunk
+	}
+
+	Hunk struct {
+		OldStart int
+		OldLines int
+		NewStart int
+		NewLines int
 	}
 
+	ChangeStatus byte
+
 	PackageInfo struct {
-		Path          string
-		Dependencies  []string
-		ContainsTests bool
+		Path                 string
+		Name                 string
+		Module               Module
+		Files                []string
+		IgnoredFiles         []string
+		Dependencies         []string
+		TestDependencies     []string
+		ExternalDependencies []string
+		Embeds               []string
+		TestEmbeds           []string
+		ContainsTests        bool
+		OtherFiles           []string
+		Manifest             *DeploymentManifest
+	}
+
+	Target struct {
+		Name   string
+		GOOS   string
+		GOARCH string
+		Tags   []string
+		Cgo    bool
+	}
+
+	Module struct {
+		Path string
+		Dir  string
+		// Workspace is set for the modules used by a go.work, which are all built with one build list.
+		Workspace bool
 	}
 
 	Config struct {
@@ -23,6 +62,50 @@ type (
 	}
 )
 
+const (
+	mainPackage = "main"
+
+	StatusAdded       ChangeStatus = 'A'
+	StatusCopied      ChangeStatus = 'C'
+	StatusDeleted     ChangeStatus = 'D'
+	StatusModified    ChangeStatus = 'M'
+	StatusRenamed     ChangeStatus = 'R'
+	StatusTypeChanged ChangeStatus = 'T'
+	StatusUnmerged    ChangeStatus = 'U'
+	StatusUnknown     ChangeStatus = 'X'
+)
+
+func (c ChangeInfo) IsDeleted() bool {
+	return c.Status == StatusDeleted
+}
+
+func (c ChangeInfo) HasOldPath() bool {
+	return c.Status == StatusRenamed || c.Status == StatusCopied
+}
+
+func (p PackageInfo) QualifiedPath() string {
+	rel, _ := filepath.Rel(p.Module.Dir, p.Path)
+	return p.Module.Dir + ":" + rel
+}
+
+func (p PackageInfo) IsExcluded() bool {
+	return len(p.Files) == 0 && len(p.IgnoredFiles) > 0
+}
+
+func (p PackageInfo) IsMain() bool {
+	return p.Name == mainPackage
+}
+
+func (p PackageInfo) IsIgnoredFile(path string) bool {
+	for _, file := range p.IgnoredFiles {
+		if file == path {
+			return true
+		}
+	}
+
+	return false
+}
+
 func NewConfig(ignoredExpressions ...string) Config {
 	expressions := make([]*regexp.Regexp, 0, len(ignoredExpressions))
 	for _, expression := range ignoredExpressions {
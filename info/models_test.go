@@ -11,3 +11,27 @@ func TestNewConfig_InvalidExpression_Panic(t *testing.T) {
 		_ = info.NewConfig("[")
 	})
 }
+
+func TestPackageInfo_QualifiedPath(t *testing.T) {
+	testCases := []struct {
+		pkg      info.PackageInfo
+		expected string
+	}{
+		{
+			pkg:      info.PackageInfo{Path: "service/api", Module: info.Module{Dir: "."}},
+			expected: ".:service/api",
+		},
+		{
+			pkg:      info.PackageInfo{Path: "services/api/handler", Module: info.Module{Dir: "services/api"}},
+			expected: "services/api:handler",
+		},
+		{
+			pkg:      info.PackageInfo{Path: "services/api", Module: info.Module{Dir: "services/api"}},
+			expected: "services/api:.",
+		},
+	}
+
+	for _, c := range testCases {
+		require.Equal(t, c.expected, c.pkg.QualifiedPath())
+	}
+}
@@ -0,0 +1,349 @@
+package info
+
+import (
+	"fmt"
+	"github.com/go-lean/bevaluate/storage"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type (
+	ModuleDiffer struct {
+		versions SourceVersions
+		runner   GoRunner
+		root     string
+	}
+
+	GoRunner interface {
+		Run(dir string, args ...string) (string, error)
+	}
+
+	ModuleChanges struct {
+		Modules   []string
+		Indirect  []string
+		Affected  []string
+		Required  []string
+		Resolved  bool
+		FullScale bool
+	}
+)
+
+const (
+	goModFile = "go.mod"
+	goSumFile = "go.sum"
+)
+
+func NewModuleDiffer(versions SourceVersions) ModuleDiffer {
+	return ModuleDiffer{versions: versions}
+}
+
+// WithGraph resolves the impact of indirect changes through the module graph of the current version,
+// which is read by running go mod graph inside the module directory below the root.
+func (d ModuleDiffer) WithGraph(runner GoRunner, root string) ModuleDiffer {
+	d.runner = runner
+	d.root = root
+	return d
+}
+
+func IsModuleFile(path string) bool {
+	name := filepath.Base(path)
+	return name == goModFile || name == goSumFile
+}
+
+func (d ModuleDiffer) Diff(change ChangeInfo) (ModuleChanges, error) {
+	if change.Status != StatusModified {
+		return ModuleChanges{FullScale: true}, nil
+	}
+
+	var changes ModuleChanges
+	var errDiff error
+
+	switch filepath.Base(change.Path) {
+	case goModFile:
+		changes, errDiff = d.diffGoMod(change.Path)
+	case goSumFile:
+		changes, errDiff = d.diffGoSum(change.Path)
+	default:
+		return ModuleChanges{}, fmt.Errorf("not a module file: %q", change.Path)
+	}
+
+	if errDiff != nil || changes.FullScale {
+		return changes, errDiff
+	}
+
+	return d.resolveIndirect(changes, filepath.Dir(change.Path)), nil
+}
+
+func (d ModuleDiffer) diffGoMod(path string) (ModuleChanges, error) {
+	oldMod, errOld := d.readGoMod(path, d.versions.Old)
+	if errOld != nil {
+		return ModuleChanges{}, fmt.Errorf("could not read previous version: %w", errOld)
+	}
+
+	newMod, errNew := d.readGoMod(path, d.versions.New)
+	if errNew != nil {
+		return ModuleChanges{}, fmt.Errorf("could not read current version: %w", errNew)
+	}
+
+	if oldMod.Go != newMod.Go || oldMod.Toolchain != newMod.Toolchain {
+		return ModuleChanges{FullScale: true}, nil
+	}
+
+	changed := make(map[string]struct{})
+	oldRequires, newRequires := requirementVersions(oldMod), requirementVersions(newMod)
+	for module, version := range oldRequires {
+		if newRequires[module] != version {
+			changed[module] = struct{}{}
+		}
+	}
+
+	for module, version := range newRequires {
+		if oldRequires[module] != version {
+			changed[module] = struct{}{}
+		}
+	}
+
+	for _, module := range symmetricDifference(oldMod.Replace, newMod.Replace, func(r storage.Replacement) string { return r.Old.Path }) {
+		changed[module] = struct{}{}
+	}
+
+	for _, module := range symmetricDifference(oldMod.Exclude, newMod.Exclude, func(v storage.ModuleVersion) string { return v.Path }) {
+		changed[module] = struct{}{}
+	}
+
+	return newModuleChanges(changed, oldMod, newMod), nil
+}
+
+func (d ModuleDiffer) diffGoSum(path string) (ModuleChanges, error) {
+	oldSums, errOld := d.versions.Old(path)
+	if errOld != nil {
+		return ModuleChanges{}, fmt.Errorf("could not read previous version: %w", errOld)
+	}
+
+	newSums, errNew := d.versions.New(path)
+	if errNew != nil {
+		return ModuleChanges{}, fmt.Errorf("could not read current version: %w", errNew)
+	}
+
+	mod, errMod := d.readGoMod(filepath.Join(filepath.Dir(path), goModFile), d.versions.New)
+	if errMod != nil {
+		return ModuleChanges{}, fmt.Errorf("could not read module file: %w", errMod)
+	}
+
+	oldLines := sumLines(oldSums)
+	newLines := sumLines(newSums)
+	required := requirementVersions(mod)
+	changed := make(map[string]struct{})
+
+	for _, lines := range [][2]map[string]struct{}{{oldLines, newLines}, {newLines, oldLines}} {
+		for line := range lines[0] {
+			if _, ok := lines[1][line]; ok {
+				continue
+			}
+
+			// checksums of modules which are not required only verify the module graph and are
+			// never built, a changed selection of them shows up in go.mod instead
+			if module := strings.Fields(line)[0]; required[module] != "" {
+				changed[module] = struct{}{}
+			}
+		}
+	}
+
+	return newModuleChanges(changed, mod, mod), nil
+}
+
+func (d ModuleDiffer) readGoMod(path string, read func(string) ([]byte, error)) (storage.GoMod, error) {
+	data, errRead := read(path)
+	if errRead != nil {
+		return storage.GoMod{}, errRead
+	}
+
+	return storage.ParseGoMod(data)
+}
+
+// resolveIndirect marks the requirements which depend on the indirect changes as affected. The changes
+// stay unresolved when the graph can not be read or does not contain some of the indirect modules.
+func (d ModuleDiffer) resolveIndirect(changes ModuleChanges, moduleDir string) ModuleChanges {
+	if len(changes.Indirect) == 0 {
+		changes.Resolved = true
+		return changes
+	}
+
+	if d.runner == nil {
+		return changes
+	}
+
+	output, errGraph := d.runner.Run(filepath.Join(d.root, moduleDir), "mod", "graph")
+	if errGraph != nil {
+		return changes
+	}
+
+	requirers := moduleRequirers(output)
+	affected := make(map[string]struct{})
+	pending := make([]string, 0, len(changes.Indirect))
+
+	for _, module := range changes.Indirect {
+		if _, ok := requirers[module]; ok == false {
+			return changes // e.g. removed from the graph
+		}
+
+		affected[module] = struct{}{}
+		pending = append(pending, module)
+	}
+
+	for len(pending) > 0 {
+		module := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		for _, requirer := range requirers[module] {
+			if _, ok := affected[requirer]; ok == false {
+				affected[requirer] = struct{}{}
+				pending = append(pending, requirer)
+			}
+		}
+	}
+
+	changes.Affected = make([]string, 0, len(affected))
+	for module := range affected {
+		changes.Affected = append(changes.Affected, module)
+	}
+
+	sort.Strings(changes.Affected)
+	changes.Resolved = true
+	return changes
+}
+
+func (c ModuleChanges) Affects(importPath string) bool {
+	if len(c.Indirect) > 0 && c.Resolved == false {
+		return true // the module graph is unknown, so an indirect change could affect any external import
+	}
+
+	owner := ""
+	for _, modules := range [][]string{c.Required, c.Modules} {
+		for _, module := range modules {
+			if isModuleImport(importPath, module) && len(module) > len(owner) {
+				owner = module
+			}
+		}
+	}
+
+	if owner == "" {
+		return len(c.Modules) > 0 || len(c.Affected) > 0
+	}
+
+	for _, modules := range [][]string{c.Modules, c.Affected} {
+		for _, module := range modules {
+			if module == owner {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func newModuleChanges(changed map[string]struct{}, oldMod, newMod storage.GoMod) ModuleChanges {
+	result := ModuleChanges{
+		Modules:  make([]string, 0, len(changed)),
+		Indirect: make([]string, 0),
+		Required: make([]string, 0, len(newMod.Require)),
+	}
+
+	requirements := make(map[string]storage.Requirement, len(oldMod.Require)+len(newMod.Require))
+	for _, mod := range []storage.GoMod{oldMod, newMod} {
+		for _, req := range mod.Require {
+			requirements[req.Path] = req // the current version wins
+		}
+	}
+
+	for _, req := range newMod.Require {
+		result.Required = append(result.Required, req.Path)
+	}
+
+	for module := range changed {
+		req, ok := requirements[module]
+		if ok == false || req.Indirect {
+			result.Indirect = append(result.Indirect, module)
+			continue
+		}
+
+		result.Modules = append(result.Modules, module)
+	}
+
+	return result
+}
+
+func requirementVersions(mod storage.GoMod) map[string]string {
+	result := make(map[string]string, len(mod.Require))
+
+	for _, req := range mod.Require {
+		result[req.Path] = req.Version
+	}
+
+	return result
+}
+
+func symmetricDifference[T comparable](old, new []T, key func(T) string) []string {
+	oldSet := make(map[T]struct{}, len(old))
+	for _, item := range old {
+		oldSet[item] = struct{}{}
+	}
+
+	newSet := make(map[T]struct{}, len(new))
+	for _, item := range new {
+		newSet[item] = struct{}{}
+	}
+
+	result := make([]string, 0)
+	for _, item := range old {
+		if _, ok := newSet[item]; ok == false {
+			result = append(result, key(item))
+		}
+	}
+
+	for _, item := range new {
+		if _, ok := oldSet[item]; ok == false {
+			result = append(result, key(item))
+		}
+	}
+
+	return result
+}
+
+func sumLines(data []byte) map[string]struct{} {
+	result := make(map[string]struct{})
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		result[line] = struct{}{}
+	}
+
+	return result
+}
+
+// moduleRequirers maps every module of a go mod graph output to the modules requiring any of its versions.
+func moduleRequirers(graph string) map[string][]string {
+	result := make(map[string][]string)
+
+	for _, line := range strings.Split(graph, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		requirer, _, _ := strings.Cut(fields[0], "@")
+		module, _, _ := strings.Cut(fields[1], "@")
+		result[module] = append(result[module], requirer)
+	}
+
+	return result
+}
+
+func isModuleImport(importPath, module string) bool {
+	return importPath == module || strings.HasPrefix(importPath, module+"/")
+}
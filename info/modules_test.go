@@ -0,0 +1,259 @@
+package info_test
+
+import (
+	"github.com/go-lean/bevaluate/info"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const modulesBaseGoMod = `module github.com/baba/is/you
+
+go 1.20
+
+require (
+	github.com/stretchr/testify v1.8.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require github.com/davecgh/go-spew v1.1.1 // indirect
+
+replace github.com/baba/is/flag => ../flag
+`
+
+type (
+	FakeGoRunner struct {
+		output string
+		err    error
+		dir    string
+	}
+)
+
+func (r *FakeGoRunner) Run(dir string, _ ...string) (string, error) {
+	r.dir = dir
+	return r.output, r.err
+}
+
+func TestModuleDiffer_Diff_GoMod(t *testing.T) {
+	testCases := []struct {
+		name             string
+		newGoMod         string
+		expectedModules  []string
+		expectedIndirect []string
+		expectedFull     bool
+	}{
+		{
+			name:             "same content should change nothing",
+			newGoMod:         modulesBaseGoMod,
+			expectedModules:  []string{},
+			expectedIndirect: []string{},
+		},
+		{
+			name:             "bumped requirement should be changed",
+			newGoMod:         replace(modulesBaseGoMod, "testify v1.8.1", "testify v1.8.2"),
+			expectedModules:  []string{"github.com/stretchr/testify"},
+			expectedIndirect: []string{},
+		},
+		{
+			name:             "bumped indirect requirement should be indirect",
+			newGoMod:         replace(modulesBaseGoMod, "go-spew v1.1.1", "go-spew v1.1.2"),
+			expectedModules:  []string{},
+			expectedIndirect: []string{"github.com/davecgh/go-spew"},
+		},
+		{
+			name:             "added requirement should be changed",
+			newGoMod:         modulesBaseGoMod + "\nrequire github.com/zyedidia/generic v1.2.1\n",
+			expectedModules:  []string{"github.com/zyedidia/generic"},
+			expectedIndirect: []string{},
+		},
+		{
+			name:             "changed replacement should be indirect when not required",
+			newGoMod:         replace(modulesBaseGoMod, "../flag", "../../flag"),
+			expectedModules:  []string{},
+			expectedIndirect: []string{"github.com/baba/is/flag"},
+		},
+		{
+			name:             "added exclusion should be changed",
+			newGoMod:         modulesBaseGoMod + "\nexclude github.com/stretchr/testify v1.8.0\n",
+			expectedModules:  []string{"github.com/stretchr/testify"},
+			expectedIndirect: []string{},
+		},
+		{
+			name:         "changed go version should be full scale",
+			newGoMod:     replace(modulesBaseGoMod, "go 1.20", "go 1.21"),
+			expectedFull: true,
+		},
+		{
+			name:         "added toolchain should be full scale",
+			newGoMod:     modulesBaseGoMod + "\ntoolchain go1.21.1\n",
+			expectedFull: true,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			versions := NewFakeVersions().MockAt("go.mod", modulesBaseGoMod, c.newGoMod)
+			differ := info.NewModuleDiffer(versions)
+
+			changes, err := differ.Diff(info.ChangeInfo{Status: info.StatusModified, Path: "go.mod"})
+
+			require.NoError(t, err)
+			require.Equal(t, c.expectedFull, changes.FullScale)
+			if c.expectedFull {
+				return
+			}
+
+			require.ElementsMatch(t, c.expectedModules, changes.Modules)
+			require.ElementsMatch(t, c.expectedIndirect, changes.Indirect)
+		})
+	}
+}
+
+func TestModuleDiffer_Diff_GoSum(t *testing.T) {
+	oldSum := `github.com/stretchr/testify v1.8.1 h1:aaa=
+github.com/stretchr/testify v1.8.1/go.mod h1:bbb=
+gopkg.in/yaml.v3 v3.0.1 h1:ccc=
+github.com/pmezard/go-difflib v1.0.0/go.mod h1:eee=
+`
+	newSum := `github.com/stretchr/testify v1.8.1 h1:aaa=
+github.com/stretchr/testify v1.8.1/go.mod h1:bbb=
+gopkg.in/yaml.v3 v3.0.1 h1:ddd=
+github.com/pmezard/go-difflib v1.0.1/go.mod h1:fff=
+`
+
+	versions := NewFakeVersions().
+		MockAt("go.sum", oldSum, newSum).
+		MockAt("go.mod", modulesBaseGoMod, modulesBaseGoMod)
+	differ := info.NewModuleDiffer(versions)
+
+	changes, err := differ.Diff(info.ChangeInfo{Status: info.StatusModified, Path: "go.sum"})
+
+	require.NoError(t, err)
+	require.False(t, changes.FullScale)
+	require.Equal(t, []string{"gopkg.in/yaml.v3"}, changes.Modules)
+	require.Empty(t, changes.Indirect)
+}
+
+func TestModuleDiffer_Diff_IndirectWithGraph(t *testing.T) {
+	graph := `github.com/baba/is/you github.com/stretchr/testify@v1.8.1
+github.com/baba/is/you gopkg.in/yaml.v3@v3.0.1
+github.com/baba/is/you github.com/davecgh/go-spew@v1.1.2
+github.com/stretchr/testify@v1.8.1 github.com/davecgh/go-spew@v1.1.1
+`
+	testCases := []struct {
+		name             string
+		runner           FakeGoRunner
+		expectedAffected []string
+		expectedResolved bool
+	}{
+		{
+			name:             "indirect change should affect its requirers",
+			runner:           FakeGoRunner{output: graph},
+			expectedAffected: []string{"github.com/baba/is/you", "github.com/davecgh/go-spew", "github.com/stretchr/testify"},
+			expectedResolved: true,
+		},
+		{
+			name:   "indirect change missing from the graph should stay unresolved",
+			runner: FakeGoRunner{output: "github.com/baba/is/you gopkg.in/yaml.v3@v3.0.1\n"},
+		},
+		{
+			name:   "unreadable graph should stay unresolved",
+			runner: FakeGoRunner{err: errKaboom},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			newGoMod := replace(modulesBaseGoMod, "go-spew v1.1.1", "go-spew v1.1.2")
+			versions := NewFakeVersions().MockAt("baba/go.mod", modulesBaseGoMod, newGoMod)
+			runner := &c.runner
+			differ := info.NewModuleDiffer(versions).WithGraph(runner, "root")
+
+			changes, err := differ.Diff(info.ChangeInfo{Status: info.StatusModified, Path: "baba/go.mod"})
+
+			require.NoError(t, err)
+			require.Equal(t, "root/baba", runner.dir)
+			require.Equal(t, []string{"github.com/davecgh/go-spew"}, changes.Indirect)
+			require.Equal(t, c.expectedAffected, changes.Affected)
+			require.Equal(t, c.expectedResolved, changes.Resolved)
+			require.Equal(t, c.expectedResolved == false, changes.Affects("gopkg.in/yaml.v3"))
+			require.True(t, changes.Affects("github.com/stretchr/testify/require"))
+		})
+	}
+}
+
+func TestModuleDiffer_Diff_NotModified_FullScale(t *testing.T) {
+	differ := info.NewModuleDiffer(NewFakeVersions())
+
+	changes, err := differ.Diff(info.ChangeInfo{Status: info.StatusAdded, Path: "go.mod"})
+
+	require.NoError(t, err)
+	require.True(t, changes.FullScale)
+}
+
+func TestModuleDiffer_Diff_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		path     string
+		versions *FakeVersions
+	}{
+		{
+			name:     "unreadable go.mod should fail",
+			path:     "go.mod",
+			versions: NewFakeVersions(),
+		},
+		{
+			name:     "invalid go.mod should fail",
+			path:     "go.mod",
+			versions: NewFakeVersions().MockAt("go.mod", "require baba", "require baba"),
+		},
+		{
+			name:     "go.sum without go.mod should fail",
+			path:     "go.sum",
+			versions: NewFakeVersions().MockAt("go.sum", "", ""),
+		},
+		{
+			name:     "other file should fail",
+			path:     "baba.go",
+			versions: NewFakeVersions(),
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			differ := info.NewModuleDiffer(c.versions)
+
+			_, err := differ.Diff(info.ChangeInfo{Status: info.StatusModified, Path: c.path})
+
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestModuleChanges_Affects(t *testing.T) {
+	changes := info.ModuleChanges{
+		Modules:  []string{"cloud.google.com/go"},
+		Required: []string{"cloud.google.com/go", "cloud.google.com/go/storage"},
+	}
+
+	require.True(t, changes.Affects("cloud.google.com/go"))
+	require.True(t, changes.Affects("cloud.google.com/go/pubsub"))
+	require.False(t, changes.Affects("cloud.google.com/go/storage/internal"))
+	require.True(t, changes.Affects("github.com/unknown/module"))
+
+	changes.Indirect = []string{"github.com/davecgh/go-spew"}
+	require.True(t, changes.Affects("cloud.google.com/go/storage"))
+
+	changes.Resolved = true
+	require.False(t, changes.Affects("cloud.google.com/go/storage"))
+
+	changes.Affected = []string{"cloud.google.com/go/storage"}
+	require.True(t, changes.Affects("cloud.google.com/go/storage"))
+
+	require.False(t, info.ModuleChanges{}.Affects("cloud.google.com/go"))
+}
+
+func TestIsModuleFile(t *testing.T) {
+	require.True(t, info.IsModuleFile("go.mod"))
+	require.True(t, info.IsModuleFile("tools/go.sum"))
+	require.False(t, info.IsModuleFile("go.modules"))
+}
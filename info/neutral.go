@@ -0,0 +1,473 @@
+package info
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	SourceVersions interface {
+		Old(path string) ([]byte, error)
+		New(path string) ([]byte, error)
+	}
+
+	NeutralChangeClassifier struct {
+		versions  SourceVersions
+		dirReader DirReader
+		root      string
+	}
+
+	normalizedFile struct {
+		packageName string
+		imports     []string
+		directives  []string
+		usesCgo     bool
+		decls       map[string]normalizedDecl
+	}
+
+	normalizedDecl struct {
+		names      []string
+		value      string
+		node       ast.Node
+		isMethod   bool
+		runsAtInit bool
+	}
+)
+
+var (
+	posType         = reflect.TypeOf(token.NoPos)
+	objectType      = reflect.TypeOf(&ast.Object{})
+	scopeType       = reflect.TypeOf(&ast.Scope{})
+	commentType     = reflect.TypeOf(&ast.CommentGroup{})
+	directivePrefix = []string{"//go:", "//export ", "//line ", "// +build"}
+)
+
+func NewNeutralChangeClassifier(versions SourceVersions, dirReader DirReader, root string) NeutralChangeClassifier {
+	return NeutralChangeClassifier{
+		versions:  versions,
+		dirReader: dirReader,
+		root:      root,
+	}
+}
+
+func (c NeutralChangeClassifier) IsNeutral(change ChangeInfo) (bool, error) {
+	if change.Status != StatusModified || strings.HasSuffix(change.Path, ".go") == false {
+		return false, nil
+	}
+
+	oldSource, errOld := c.versions.Old(change.Path)
+	if errOld != nil {
+		return false, fmt.Errorf("could not read previous version: %w", errOld)
+	}
+
+	newSource, errNew := c.versions.New(change.Path)
+	if errNew != nil {
+		return false, fmt.Errorf("could not read current version: %w", errNew)
+	}
+
+	oldFile, errParseOld := normalizeSource(change.Path, oldSource)
+	if errParseOld != nil {
+		return false, fmt.Errorf("could not parse previous version: %w", errParseOld)
+	}
+
+	newFile, errParseNew := normalizeSource(change.Path, newSource)
+	if errParseNew != nil {
+		return false, fmt.Errorf("could not parse current version: %w", errParseNew)
+	}
+
+	if oldFile.usesCgo || newFile.usesCgo ||
+		oldFile.packageName != newFile.packageName ||
+		equalStrings(oldFile.imports, newFile.imports) == false ||
+		equalStrings(oldFile.directives, newFile.directives) == false {
+		return false, nil
+	}
+
+	changed := changedDecls(oldFile, newFile)
+	if len(changed) == 0 {
+		return true, nil
+	}
+
+	for _, decl := range changed {
+		if decl.isMethod || decl.runsAtInit || isPrivateDecl(newFile.packageName, decl.names) == false {
+			return false, nil
+		}
+	}
+
+	used, errUsed := c.areDeclsUsed(change.Path, newFile, changed)
+	if errUsed != nil {
+		return false, fmt.Errorf("could not determine declaration usages: %w", errUsed)
+	}
+
+	return used == false, nil
+}
+
+func (c NeutralChangeClassifier) areDeclsUsed(path string, file normalizedFile, decls []normalizedDecl) (bool, error) {
+	names := make(map[string]struct{}, len(decls))
+	own := make(map[*ast.Ident]struct{}, len(decls))
+
+	for _, decl := range decls {
+		for _, name := range decl.names {
+			names[name] = struct{}{}
+		}
+
+		if decl.node == nil {
+			continue // removed declaration
+		}
+
+		ast.Inspect(decl.node, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Ident); ok {
+				own[ident] = struct{}{}
+			}
+			return true
+		})
+	}
+
+	for _, decl := range file.decls {
+		if decl.node == nil {
+			continue
+		}
+
+		if isReferenced(decl.node, names, own) {
+			return true, nil
+		}
+	}
+
+	siblings, errSiblings := c.readSiblings(path)
+	if errSiblings != nil {
+		return false, errSiblings
+	}
+
+	for _, sibling := range siblings {
+		if isReferenced(sibling, names, own) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (c NeutralChangeClassifier) readSiblings(path string) ([]*ast.File, error) {
+	dir := filepath.Dir(path)
+	entries, errRead := c.dirReader.Read(filepath.Join(c.root, dir))
+	if errRead != nil {
+		return nil, fmt.Errorf("could not read package dir: %w", errRead)
+	}
+
+	result := make([]*ast.File, 0, len(entries))
+	for _, entry := range entries {
+		siblingPath := filepath.Join(dir, entry.Name())
+		if entry.IsDir() || strings.HasSuffix(siblingPath, ".go") == false || siblingPath == path {
+			continue
+		}
+
+		source, errSource := c.versions.New(siblingPath)
+		if errSource != nil {
+			return nil, fmt.Errorf("could not read package file: %w", errSource)
+		}
+
+		parsedFile, errParse := parser.ParseFile(token.NewFileSet(), siblingPath, source, parser.SkipObjectResolution)
+		if errParse != nil {
+			return nil, fmt.Errorf("could not parse package file: %w", errParse)
+		}
+
+		result = append(result, parsedFile)
+	}
+
+	return result, nil
+}
+
+func normalizeSource(path string, source []byte) (normalizedFile, error) {
+	parsedFile, errParse := parser.ParseFile(token.NewFileSet(), path, source, parser.ParseComments|parser.SkipObjectResolution)
+	if errParse != nil {
+		return normalizedFile{}, errParse
+	}
+
+	result := normalizedFile{
+		packageName: parsedFile.Name.Name,
+		imports:     make([]string, 0, len(parsedFile.Imports)),
+		directives:  make([]string, 0),
+		decls:       make(map[string]normalizedDecl, len(parsedFile.Decls)),
+	}
+
+	for _, imp := range parsedFile.Imports {
+		name := ""
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+
+		if imp.Path.Value == `"C"` {
+			result.usesCgo = true
+		}
+
+		result.imports = append(result.imports, name+" "+imp.Path.Value)
+	}
+	sort.Strings(result.imports)
+
+	for _, group := range parsedFile.Comments {
+		for _, comment := range group.List {
+			if isDirective(comment.Text) {
+				result.directives = append(result.directives, strings.TrimSpace(comment.Text))
+			}
+		}
+	}
+
+	// keys of repeated declarations like init functions or blank vars are numbered in order
+	addDecl := func(key string, decl normalizedDecl) {
+		uniqueKey := key
+		for i := 1; ; i++ {
+			if _, ok := result.decls[uniqueKey]; ok == false {
+				break
+			}
+
+			uniqueKey = key + "#" + strconv.Itoa(i)
+		}
+
+		result.decls[uniqueKey] = decl
+	}
+
+	for _, decl := range parsedFile.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			key := "func " + d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				key = "method " + serialize(d.Recv.List[0].Type) + "." + d.Name.Name
+			}
+
+			addDecl(key, normalizedDecl{
+				names:    []string{d.Name.Name},
+				value:    serialize(d),
+				node:     d,
+				isMethod: d.Recv != nil,
+			})
+		case *ast.GenDecl:
+			if d.Tok == token.IMPORT {
+				continue
+			}
+
+			if d.Tok == token.CONST {
+				// const specs depend on their position inside the group because of iota
+				names := specNames(d.Specs...)
+				addDecl("const "+strings.Join(names, ","), normalizedDecl{
+					names: names,
+					value: serialize(d),
+					node:  d,
+				})
+				continue
+			}
+
+			for _, spec := range d.Specs {
+				names := specNames(spec)
+				addDecl(d.Tok.String()+" "+strings.Join(names, ","), normalizedDecl{
+					names:      names,
+					value:      serialize(spec),
+					node:       spec,
+					runsAtInit: isInitializedAtRuntime(spec),
+				})
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func changedDecls(oldFile, newFile normalizedFile) []normalizedDecl {
+	result := make([]normalizedDecl, 0)
+
+	for key, newDecl := range newFile.decls {
+		oldDecl, ok := oldFile.decls[key]
+		if ok && oldDecl.value == newDecl.value {
+			continue
+		}
+
+		newDecl.runsAtInit = newDecl.runsAtInit || oldDecl.runsAtInit
+		result = append(result, newDecl)
+	}
+
+	for key, oldDecl := range oldFile.decls {
+		if _, ok := newFile.decls[key]; ok {
+			continue
+		}
+
+		result = append(result, normalizedDecl{names: oldDecl.names, isMethod: oldDecl.isMethod, runsAtInit: oldDecl.runsAtInit})
+	}
+
+	return result
+}
+
+func isPrivateDecl(packageName string, names []string) bool {
+	for _, name := range names {
+		if name == "_" || name == "init" || packageName == "main" && name == "main" || ast.IsExported(name) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isInitializedAtRuntime reports whether a var spec has an initializer which is not built from constant
+// expressions, like a call, since it is evaluated at package initialization even when the var is unused.
+func isInitializedAtRuntime(spec ast.Spec) bool {
+	valueSpec, ok := spec.(*ast.ValueSpec)
+	if ok == false {
+		return false
+	}
+
+	for _, value := range valueSpec.Values {
+		if isConstantExpr(value) == false {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isConstantExpr(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case *ast.BasicLit, *ast.Ident:
+		return true
+	case *ast.SelectorExpr:
+		return isConstantExpr(e.X)
+	case *ast.ParenExpr:
+		return isConstantExpr(e.X)
+	case *ast.UnaryExpr:
+		return e.Op != token.ARROW && isConstantExpr(e.X)
+	case *ast.BinaryExpr:
+		return isConstantExpr(e.X) && isConstantExpr(e.Y)
+	case *ast.KeyValueExpr:
+		return isConstantExpr(e.Key) && isConstantExpr(e.Value)
+	case *ast.CompositeLit:
+		for _, elt := range e.Elts {
+			if isConstantExpr(elt) == false {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return false
+	}
+}
+
+func isReferenced(node ast.Node, names map[string]struct{}, own map[*ast.Ident]struct{}) bool {
+	referenced := false
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		if referenced {
+			return false
+		}
+
+		ident, ok := n.(*ast.Ident)
+		if ok == false {
+			return true
+		}
+
+		if _, isOwn := own[ident]; isOwn {
+			return true
+		}
+
+		if _, isName := names[ident.Name]; isName {
+			referenced = true
+		}
+
+		return true
+	})
+
+	return referenced
+}
+
+func specNames(specs ...ast.Spec) []string {
+	result := make([]string, 0, len(specs))
+
+	for _, spec := range specs {
+		switch s := spec.(type) {
+		case *ast.ValueSpec:
+			for _, name := range s.Names {
+				result = append(result, name.Name)
+			}
+		case *ast.TypeSpec:
+			result = append(result, s.Name.Name)
+		}
+	}
+
+	return result
+}
+
+func isDirective(comment string) bool {
+	for _, prefix := range directivePrefix {
+		if strings.HasPrefix(comment, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func serialize(node any) string {
+	sb := strings.Builder{}
+	writeValue(&sb, reflect.ValueOf(node))
+
+	return sb.String()
+}
+
+func writeValue(sb *strings.Builder, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			sb.WriteString("nil")
+			return
+		}
+
+		writeValue(sb, v.Elem())
+	case reflect.Struct:
+		sb.WriteString(v.Type().Name())
+		sb.WriteByte('{')
+
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.Type == posType || field.Type == objectType || field.Type == scopeType || field.Type == commentType {
+				continue // formatting and comments are not part of the semantics
+			}
+
+			sb.WriteString(field.Name)
+			sb.WriteByte(':')
+			writeValue(sb, v.Field(i))
+			sb.WriteByte(';')
+		}
+
+		sb.WriteByte('}')
+	case reflect.Slice:
+		sb.WriteByte('[')
+
+		for i := 0; i < v.Len(); i++ {
+			writeValue(sb, v.Index(i))
+			sb.WriteByte(',')
+		}
+
+		sb.WriteByte(']')
+	case reflect.String:
+		sb.WriteString(strconv.Quote(v.String()))
+	default:
+		sb.WriteString(fmt.Sprint(v.Interface()))
+	}
+}
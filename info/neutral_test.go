@@ -0,0 +1,324 @@
+package info_test
+
+import (
+	"github.com/go-lean/bevaluate/info"
+	"github.com/go-lean/bevaluate/models"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type (
+	FakeVersions struct {
+		old map[string]string
+		new map[string]string
+	}
+)
+
+func NewFakeVersions() *FakeVersions {
+	return &FakeVersions{
+		old: make(map[string]string),
+		new: make(map[string]string),
+	}
+}
+
+func (v *FakeVersions) MockAt(path, oldSource, newSource string) *FakeVersions {
+	v.old[path] = oldSource
+	v.new[path] = newSource
+	return v
+}
+
+func (v *FakeVersions) Old(path string) ([]byte, error) {
+	source, ok := v.old[path]
+	if ok == false {
+		return nil, errKaboom
+	}
+
+	return []byte(source), nil
+}
+
+func (v *FakeVersions) New(path string) ([]byte, error) {
+	source, ok := v.new[path]
+	if ok == false {
+		return nil, errKaboom
+	}
+
+	return []byte(source), nil
+}
+
+const neutralBaseSource = `package baba
+
+import "fmt"
+
+// Is prints the rule.
+func Is(you string) {
+	fmt.Println("baba is " + you)
+}
+`
+
+func TestNeutralChangeClassifier_IsNeutral_NotModifiedGoFile_False(t *testing.T) {
+	classifier := info.NewNeutralChangeClassifier(NewFakeVersions(), NewDirReader(), "root")
+
+	for _, change := range []info.ChangeInfo{
+		{Status: info.StatusAdded, Path: "baba/baba.go"},
+		{Status: info.StatusModified, Path: "baba/baba.txt"},
+	} {
+		neutral, err := classifier.IsNeutral(change)
+
+		require.NoError(t, err)
+		require.False(t, neutral)
+	}
+}
+
+func TestNeutralChangeClassifier_IsNeutral_ReadError(t *testing.T) {
+	classifier := info.NewNeutralChangeClassifier(NewFakeVersions(), NewDirReader(), "root")
+
+	neutral, err := classifier.IsNeutral(info.ChangeInfo{Status: info.StatusModified, Path: "baba/baba.go"})
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "kaboom")
+	require.False(t, neutral)
+}
+
+func TestNeutralChangeClassifier_IsNeutral_ParseError(t *testing.T) {
+	versions := NewFakeVersions().MockAt("baba/baba.go", neutralBaseSource, "bad go code")
+	classifier := info.NewNeutralChangeClassifier(versions, NewDirReader(), "root")
+
+	neutral, err := classifier.IsNeutral(info.ChangeInfo{Status: info.StatusModified, Path: "baba/baba.go"})
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "parse")
+	require.False(t, neutral)
+}
+
+func TestNeutralChangeClassifier_IsNeutral_FileOnlyChanges(t *testing.T) {
+	testCases := []struct {
+		name      string
+		newSource string
+		expected  bool
+	}{
+		{
+			name: "comment change should be neutral",
+			newSource: `package baba
+
+import "fmt"
+
+// Is prints the rule, baba is always you.
+func Is(you string) {
+	// the rule is printed
+	fmt.Println("baba is " + you)
+}
+`,
+			expected: true,
+		},
+		{
+			name: "formatting change should be neutral",
+			newSource: `package baba
+import (
+	"fmt"
+)
+func Is(you string) { fmt.Println("baba is " +
+	you) }
+`,
+			expected: true,
+		},
+		{
+			name: "body change should not be neutral",
+			newSource: `package baba
+
+import "fmt"
+
+func Is(you string) {
+	fmt.Println("baba is not " + you)
+}
+`,
+		},
+		{
+			name: "import change should not be neutral",
+			newSource: `package baba
+
+import fmt "log"
+
+func Is(you string) {
+	fmt.Println("baba is " + you)
+}
+`,
+		},
+		{
+			name: "directive change should not be neutral",
+			newSource: `package baba
+
+import "fmt"
+
+//go:noinline
+func Is(you string) {
+	fmt.Println("baba is " + you)
+}
+`,
+		},
+		{
+			name: "unexported function used in the same file should not be neutral",
+			newSource: `package baba
+
+import "fmt"
+
+func Is(you string) {
+	fmt.Println(rule(you))
+}
+
+func rule(you string) string {
+	return "baba is " + you
+}
+`,
+		},
+		{
+			name: "unexported method should not be neutral",
+			newSource: `package baba
+
+import "fmt"
+
+func Is(you string) {
+	fmt.Println("baba is " + you)
+}
+
+type rule struct{}
+
+func (rule) apply() {}
+`,
+		},
+		{
+			name: "blank declaration should not be neutral",
+			newSource: `package baba
+
+import "fmt"
+
+func Is(you string) {
+	fmt.Println("baba is " + you)
+}
+
+var _ = fmt.Sprint("you")
+`,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			versions := NewFakeVersions().MockAt("baba/baba.go", neutralBaseSource, c.newSource)
+			dirReader := NewDirReader()
+			dirReader.MockAt("root/baba", []models.DirEntry{DirEntry{name: "baba.go"}})
+			classifier := info.NewNeutralChangeClassifier(versions, dirReader, "root")
+
+			neutral, err := classifier.IsNeutral(info.ChangeInfo{Status: info.StatusModified, Path: "baba/baba.go"})
+
+			require.NoError(t, err)
+			require.Equal(t, c.expected, neutral)
+		})
+	}
+}
+
+func TestNeutralChangeClassifier_IsNeutral_UnexportedDeclaration(t *testing.T) {
+	newSource := neutralBaseSource + `
+var rules = []string{"baba", "is", "you"}
+`
+
+	testCases := []struct {
+		name          string
+		siblingSource string
+		expected      bool
+	}{
+		{
+			name:          "unused in the package should be neutral",
+			siblingSource: "package baba\n\nfunc Flag() string { return \"flag\" }\n",
+			expected:      true,
+		},
+		{
+			name:          "used in the package should not be neutral",
+			siblingSource: "package baba\n\nfunc Flag() string { return rules[0] }\n",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			versions := NewFakeVersions().
+				MockAt("baba/baba.go", neutralBaseSource, newSource).
+				MockAt("baba/flag.go", c.siblingSource, c.siblingSource)
+			dirReader := NewDirReader()
+			dirReader.MockAt("root/baba", []models.DirEntry{
+				DirEntry{name: "baba.go"},
+				DirEntry{name: "flag.go"},
+				DirEntry{name: "testdata", isDir: true},
+				DirEntry{name: "README.md"},
+			})
+			classifier := info.NewNeutralChangeClassifier(versions, dirReader, "root")
+
+			neutral, err := classifier.IsNeutral(info.ChangeInfo{Status: info.StatusModified, Path: "baba/baba.go"})
+
+			require.NoError(t, err)
+			require.Equal(t, c.expected, neutral)
+		})
+	}
+}
+
+func TestNeutralChangeClassifier_IsNeutral_UnusedVarInitializer(t *testing.T) {
+	testCases := []struct {
+		name     string
+		oldVar   string
+		newVar   string
+		expected bool
+	}{
+		{
+			name:     "changed constant initializer should be neutral",
+			oldVar:   `var limit = 2 * 3`,
+			newVar:   `var limit = -(2 + 4)`,
+			expected: true,
+		},
+		{
+			name:   "changed call argument should not be neutral",
+			oldVar: `var registered = fmt.Sprint("a")`,
+			newVar: `var registered = fmt.Sprint("b")`,
+		},
+		{
+			name:   "replaced call should not be neutral",
+			oldVar: `var registered = fmt.Sprint("a")`,
+			newVar: `var registered = "a"`,
+		},
+		{
+			name:   "removed var with a call should not be neutral",
+			oldVar: `var registered = fmt.Sprint("a")`,
+		},
+		{
+			name:   "call inside a composite literal should not be neutral",
+			oldVar: `var rules = []string{"baba"}`,
+			newVar: `var rules = []string{fmt.Sprint("baba")}`,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			versions := NewFakeVersions().
+				MockAt("baba/baba.go", neutralBaseSource+"\n"+c.oldVar+"\n", neutralBaseSource+"\n"+c.newVar+"\n")
+			dirReader := NewDirReader()
+			dirReader.MockAt("root/baba", []models.DirEntry{DirEntry{name: "baba.go"}})
+			classifier := info.NewNeutralChangeClassifier(versions, dirReader, "root")
+
+			neutral, err := classifier.IsNeutral(info.ChangeInfo{Status: info.StatusModified, Path: "baba/baba.go"})
+
+			require.NoError(t, err)
+			require.Equal(t, c.expected, neutral)
+		})
+	}
+}
+
+func TestNeutralChangeClassifier_IsNeutral_UnexportedDeclarationSiblingError(t *testing.T) {
+	newSource := neutralBaseSource + `
+func rule() {}
+`
+	versions := NewFakeVersions().MockAt("baba/baba.go", neutralBaseSource, newSource)
+	dirReader := NewDirReader().CanRead(false)
+	classifier := info.NewNeutralChangeClassifier(versions, dirReader, "root")
+
+	neutral, err := classifier.IsNeutral(info.ChangeInfo{Status: info.StatusModified, Path: "baba/baba.go"})
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "kaboom")
+	require.False(t, neutral)
+}
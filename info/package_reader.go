@@ -1,10 +1,12 @@
 package info
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/go-lean/bevaluate/models"
 	"github.com/go-lean/bevaluate/util"
 	"github.com/zyedidia/generic/stack"
+	"go/build"
 	"go/parser"
 	"go/token"
 	"io"
@@ -17,6 +19,7 @@ type (
 		fileOpener FileOpener
 		dirReader  DirReader
 		config     Config
+		target     *Target
 	}
 
 	FileOpener interface {
@@ -36,36 +39,94 @@ func NewPackageReader(dirReader DirReader, fileOpener FileOpener, cfg Config) Pa
 	}
 }
 
+func (r PackageReader) WithTarget(target Target) PackageReader {
+	r.target = &target
+	return r
+}
+
 func (r PackageReader) ReadRecursively(root, moduleName string) ([]PackageInfo, error) {
-	entries, errRead := r.dirReader.Read(root)
-	if errRead != nil {
-		return nil, fmt.Errorf("could not read root directory: %w", errRead)
-	}
+	return r.ReadModules(root, []Module{{Path: moduleName, Dir: rootDir}})
+}
 
-	dirs := make([]string, 0, len(entries))
-	for _, entry := range entries {
-		if entry.IsDir() == false || r.config.IsIgnored(entry.Name()) {
+func (r PackageReader) ReadModules(root string, modules []Module) ([]PackageInfo, error) {
+	result := make([]PackageInfo, 0)
+
+	for _, module := range modules {
+		if module.Dir != rootDir {
+			packages, errRead := r.readSubDirsRecursively(root, modules, []string{module.Dir})
+			if errRead != nil {
+				return nil, fmt.Errorf("could not read module %q: %w", module.Path, errRead)
+			}
+
+			result = append(result, packages...)
 			continue
 		}
 
-		dirs = append(dirs, entry.Name())
+		entries, errRead := r.dirReader.Read(root)
+		if errRead != nil {
+			return nil, fmt.Errorf("could not read root directory: %w", errRead)
+		}
+
+		dirs := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			if entry.IsDir() == false || r.config.IsIgnored(entry.Name()) || isModuleDir(entry.Name(), modules) {
+				continue
+			}
+
+			dirs = append(dirs, entry.Name())
+		}
+
+		packages, errRead := r.readSubDirsRecursively(root, modules, dirs)
+		if errRead != nil {
+			return nil, fmt.Errorf("could not read root sub dirs: %w", errRead)
+		}
+
+		result = append(result, packages...)
 	}
 
-	result, errRead := r.readSubDirsRecursively(root, moduleName, dirs)
-	if errRead != nil {
-		return nil, fmt.Errorf("could not read root sub dirs: %w", errRead)
+	return result, nil
+}
+
+// ReadIgnoredDirs returns the ignored directories below the root. Their packages are never read, but
+// patterns like ./... would still include them, unlike the directories skipped by go itself.
+func (r PackageReader) ReadIgnoredDirs(root string) ([]string, error) {
+	result := make([]string, 0)
+	dirsStack := stack.New[string]()
+	dirsStack.Push(rootDir)
+
+	for dirsStack.Size() > 0 {
+		dir := dirsStack.Pop()
+
+		entries, errRead := r.dirReader.Read(filepath.Join(root, dir))
+		if errRead != nil {
+			return nil, fmt.Errorf("could not read dir: %w", errRead)
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() == false || isSkippedByGo(entry.Name()) {
+				continue
+			}
+
+			entryPath := filepath.Join(dir, entry.Name())
+			if r.config.IsIgnored(entryPath) {
+				result = append(result, entryPath)
+				continue
+			}
+
+			dirsStack.Push(entryPath)
+		}
 	}
 
 	return result, nil
 }
 
-func (r PackageReader) readSubDirsRecursively(root, moduleName string, dirs []string) ([]PackageInfo, error) {
+func (r PackageReader) readSubDirsRecursively(root string, modules []Module, dirs []string) ([]PackageInfo, error) {
 	errChan := make(chan error, 1)
 	pkgChan := make(chan []PackageInfo)
 
 	for _, dir := range dirs {
 		go func(dirPath string) {
-			packages, errRead := r.readSubDirRecursively(root, moduleName, dirPath)
+			packages, errRead := r.readSubDirRecursively(root, modules, dirPath)
 			if errRead != nil {
 				errChan <- errRead
 				return
@@ -88,7 +149,7 @@ func (r PackageReader) readSubDirsRecursively(root, moduleName string, dirs []st
 	return result, nil
 }
 
-func (r PackageReader) readSubDirRecursively(root, moduleName, subDir string) ([]PackageInfo, error) {
+func (r PackageReader) readSubDirRecursively(root string, modules []Module, subDir string) ([]PackageInfo, error) {
 	dirsStack := stack.New[string]()
 	dirsStack.Push(subDir)
 
@@ -102,25 +163,47 @@ func (r PackageReader) readSubDirRecursively(root, moduleName, subDir string) ([
 			return nil, fmt.Errorf("could not read dir: %w", errRead)
 		}
 
-		sourceFiles := r.processEntries(dir, entries, dirsStack)
+		sourceFiles, otherFiles := r.processEntries(dir, entries, modules, dirsStack)
 		if len(sourceFiles) == 0 {
 			continue
 		}
 
-		pkg, errRead := r.readPackage(root, dir, moduleName, sourceFiles)
+		pkg, errRead := r.readPackage(root, dir, modules, sourceFiles)
 		if errRead != nil {
 			return nil, fmt.Errorf("could not read package: %w", errRead)
 		}
 
+		pkg.OtherFiles = otherFiles
+
+		for _, file := range otherFiles {
+			if filepath.Base(file) != ManifestFile {
+				continue
+			}
+
+			manifest, errManifest := readDeploymentManifest(filepath.Join(root, file), r.fileOpener)
+			if errManifest != nil {
+				return nil, errManifest
+			}
+
+			pkg.Manifest = manifest
+		}
+
 		result = append(result, pkg)
 	}
 
 	return result, nil
 }
 
-func (r PackageReader) readPackage(root, dir, moduleName string, sourceFiles []string) (PackageInfo, error) {
+func (r PackageReader) readPackage(root, dir string, modules []Module, sourceFiles []string) (PackageInfo, error) {
 	dependencies := make(map[string]struct{}, 0)
+	testDependencies := make(map[string]struct{}, 0)
+	externalDependencies := make(map[string]struct{}, 0)
+	embeds := make([]string, 0)
+	testEmbeds := make([]string, 0)
+	files := make([]string, 0, len(sourceFiles))
+	ignoredFiles := make([]string, 0)
 	containsTests := false
+	name := ""
 
 	for _, filePath := range sourceFiles {
 		file, errRead := r.fileOpener.OpenRead(filepath.Join(root, filePath))
@@ -128,11 +211,45 @@ func (r PackageReader) readPackage(root, dir, moduleName string, sourceFiles []s
 			return PackageInfo{}, fmt.Errorf("could not read source file: %w", errRead)
 		}
 
-		parsedFile, errParse := parser.ParseFile(&token.FileSet{}, filePath, file, parser.ImportsOnly)
+		source, errRead := io.ReadAll(file)
+		if errRead != nil {
+			return PackageInfo{}, fmt.Errorf("could not read source file: %w", errRead)
+		}
+
+		matches, errMatch := r.matchesTarget(filePath, source)
+		if errMatch != nil {
+			return PackageInfo{}, fmt.Errorf("could not match build constraints: %w", errMatch)
+		}
+
+		if matches == false {
+			ignoredFiles = append(ignoredFiles, filePath)
+			continue
+		}
+
+		files = append(files, filePath)
+
+		parsedFile, errParse := parser.ParseFile(&token.FileSet{}, filePath, source, parser.ImportsOnly)
 		if errParse != nil {
 			return PackageInfo{}, fmt.Errorf("could not parse source file: %w", errParse)
 		}
 
+		if name == "" && strings.HasSuffix(filePath, "_test.go") == false {
+			name = parsedFile.Name.Name
+		}
+
+		if bytes.Contains(source, []byte(embedDirective)) {
+			patterns, errEmbeds := readEmbedPatterns(filePath, source)
+			if errEmbeds != nil {
+				return PackageInfo{}, fmt.Errorf("could not read embed patterns: %w", errEmbeds)
+			}
+
+			if strings.HasSuffix(filePath, "_test.go") {
+				testEmbeds = append(testEmbeds, patterns...)
+			} else {
+				embeds = append(embeds, patterns...)
+			}
+		}
+
 		for _, imp := range parsedFile.Imports {
 			impPath := strings.Trim(imp.Path.Value, "\"")
 
@@ -141,44 +258,101 @@ func (r PackageReader) readPackage(root, dir, moduleName string, sourceFiles []s
 				continue
 			}
 
-			if strings.HasPrefix(impPath, moduleName) == false {
+			dependency, internal := resolveImport(impPath, modules)
+			if internal == false {
+				if isStandardLibrary(impPath) == false {
+					externalDependencies[impPath] = struct{}{}
+				}
+
 				continue // non internal dependency
 			}
 
-			dependency, _ := filepath.Rel(moduleName, impPath)
 			if dependency == dir || r.config.IsIgnored(dependency) {
 				continue
 			}
 
+			if strings.HasSuffix(filePath, "_test.go") {
+				testDependencies[dependency] = struct{}{}
+				continue
+			}
+
 			dependencies[dependency] = struct{}{}
 		}
 	}
 
+	for dependency := range dependencies {
+		delete(testDependencies, dependency) // already reached through the production code
+	}
+
+	module, _ := ModuleOf(dir, modules)
+
 	return PackageInfo{
-		Path:          dir,
-		Dependencies:  util.MapKeys(dependencies),
-		ContainsTests: containsTests,
+		Path:                 dir,
+		Name:                 name,
+		Module:               module,
+		Files:                files,
+		IgnoredFiles:         ignoredFiles,
+		Dependencies:         util.MapKeys(dependencies),
+		TestDependencies:     util.MapKeys(testDependencies),
+		ExternalDependencies: util.MapKeys(externalDependencies),
+		Embeds:               embeds,
+		TestEmbeds:           testEmbeds,
+		ContainsTests:        containsTests,
 	}, nil
 }
 
-func (r PackageReader) processEntries(dirPath string, entries []models.DirEntry, dirsStack *stack.Stack[string]) []string {
+func (r PackageReader) matchesTarget(filePath string, source []byte) (bool, error) {
+	if r.target == nil {
+		return true, nil
+	}
+
+	ctx := r.target.context()
+	ctx.OpenFile = func(string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(source)), nil
+	}
+
+	return ctx.MatchFile(filepath.Dir(filePath), filepath.Base(filePath))
+}
+
+func (r PackageReader) processEntries(dirPath string, entries []models.DirEntry, modules []Module, dirsStack *stack.Stack[string]) ([]string, []string) {
 	sourceFiles := make([]string, 0, len(entries))
+	otherFiles := make([]string, 0)
 
 	for _, entry := range entries {
 		entryPath := filepath.Join(dirPath, entry.Name())
 		if entry.IsDir() {
-			if r.config.IsIgnored(entryPath) == false {
+			if r.config.IsIgnored(entryPath) == false && isModuleDir(entryPath, modules) == false {
 				dirsStack.Push(entryPath)
 			}
 			continue
 		}
 
 		if strings.HasSuffix(entryPath, ".go") == false {
+			otherFiles = append(otherFiles, entryPath)
 			continue
 		}
 
 		sourceFiles = append(sourceFiles, entryPath)
 	}
 
-	return sourceFiles
+	return sourceFiles, otherFiles
+}
+
+func isSkippedByGo(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor"
+}
+
+func isStandardLibrary(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return strings.Contains(first, ".") == false
+}
+
+func (t Target) context() build.Context {
+	ctx := build.Default
+	ctx.GOOS = t.GOOS
+	ctx.GOARCH = t.GOARCH
+	ctx.BuildTags = t.Tags
+	ctx.CgoEnabled = t.Cgo
+
+	return ctx
 }
@@ -267,6 +267,42 @@ import (
 	require.Len(t, packages, 1)
 
 	require.Empty(t, packages[0].Dependencies)
+	require.Equal(t, []string{"github.com/some/dependency"}, packages[0].ExternalDependencies)
+}
+
+func TestPackageReader_ReadRecursively_ModuleNamePrefix_ShouldBeExternal(t *testing.T) {
+	dirReader := NewDirReader()
+	dirReader.MockAt("baba", []models.DirEntry{
+		DirEntry{
+			name:  "serviceone",
+			isDir: true,
+		},
+	})
+	dirReader.MockAt("baba/serviceone", []models.DirEntry{
+		DirEntry{
+			name: "baba.go",
+		},
+	})
+
+	opener := NewFileOpener()
+	opener.MockAt("baba/serviceone/baba.go", NewFakeFile(
+		`
+package serviceone
+
+import (
+	"github.com/baba/is/youtube"
+)
+`))
+
+	r := info.NewPackageReader(dirReader, opener, emptyConfig)
+
+	packages, errRead := r.ReadRecursively("baba", testModuleName)
+
+	require.NoError(t, errRead)
+	require.Len(t, packages, 1)
+
+	require.Empty(t, packages[0].Dependencies)
+	require.Equal(t, []string{"github.com/baba/is/youtube"}, packages[0].ExternalDependencies)
 }
 
 func TestPackageReader_ReadRecursively_InternalDependency_ShouldHaveOneDependency(t *testing.T) {
@@ -306,7 +342,7 @@ import (
 	require.Equal(t, "common", packages[0].Dependencies[0])
 }
 
-func TestPackageReader_ReadRecursively_InternalDependencyInTestFile_ShouldHaveOneDependency(t *testing.T) {
+func TestPackageReader_ReadRecursively_InternalDependencyInTestFile_ShouldHaveOneTestDependency(t *testing.T) {
 	dirReader := NewDirReader()
 	dirReader.MockAt("baba", []models.DirEntry{
 		DirEntry{
@@ -351,11 +387,57 @@ import (
 	require.NoError(t, errRead)
 	require.Len(t, packages, 1)
 
-	require.Len(t, packages[0].Dependencies, 1)
-	require.Equal(t, "common", packages[0].Dependencies[0])
+	require.Empty(t, packages[0].Dependencies)
+	require.Equal(t, []string{"common"}, packages[0].TestDependencies)
 	require.True(t, packages[0].ContainsTests)
 }
 
+func TestPackageReader_ReadRecursively_DependencyInBothFiles_ShouldOnlyBeProduction(t *testing.T) {
+	dirReader := NewDirReader()
+	dirReader.MockAt("baba", []models.DirEntry{
+		DirEntry{
+			name:  "serviceone",
+			isDir: true,
+		},
+	})
+	dirReader.MockAt("baba/serviceone", []models.DirEntry{
+		DirEntry{
+			name: "baba.go",
+		},
+		DirEntry{
+			name: "baba_test.go",
+		},
+	})
+
+	opener := NewFileOpener()
+	opener.MockAt("baba/serviceone/baba.go", NewFakeFile(
+		`
+package serviceone
+
+import "github.com/baba/is/you/common"
+`))
+	opener.MockAt("baba/serviceone/baba_test.go", NewFakeFile(
+		`
+package serviceone
+
+import (
+	"testing"
+	"github.com/baba/is/you/common"
+	"github.com/baba/is/you/testutil"
+)
+`))
+
+	r := info.NewPackageReader(dirReader, opener, emptyConfig)
+
+	packages, errRead := r.ReadRecursively("baba", testModuleName)
+
+	require.NoError(t, errRead)
+	require.Len(t, packages, 1)
+
+	require.Equal(t, []string{"common"}, packages[0].Dependencies)
+	require.Equal(t, []string{"testutil"}, packages[0].TestDependencies)
+}
+
 func TestPackageReader_ReadRecursively_InnerIgnoredSubDir_ShouldNotBeEmpty(t *testing.T) {
 	dirReader := NewDirReader()
 	dirReader.MockAt("baba", []models.DirEntry{
@@ -553,3 +635,214 @@ import "github.com/baba/is/you/other"
 	require.Equal(t, "service", packages[0].Path)
 	require.Empty(t, packages[0].Dependencies)
 }
+
+func TestPackageReader_ReadModules_CrossModuleDependencies(t *testing.T) {
+	dirReader := NewDirReader()
+	dirReader.MockAt("baba", []models.DirEntry{
+		DirEntry{name: "go.mod"},
+		DirEntry{name: "common", isDir: true},
+		DirEntry{name: "services", isDir: true},
+	})
+	dirReader.MockAt("baba/common", []models.DirEntry{DirEntry{name: "common.go"}})
+	dirReader.MockAt("baba/services", []models.DirEntry{DirEntry{name: "api", isDir: true}})
+	dirReader.MockAt("baba/services/api", []models.DirEntry{
+		DirEntry{name: "go.mod"},
+		DirEntry{name: "main.go"},
+		DirEntry{name: "handler", isDir: true},
+	})
+	dirReader.MockAt("baba/services/api/handler", []models.DirEntry{DirEntry{name: "handler.go"}})
+
+	opener := NewFileOpener()
+	opener.MockAt("baba/common/common.go", NewFakeFile("package common\n"))
+	opener.MockAt("baba/services/api/main.go", NewFakeFile(`
+package main
+
+import (
+	"github.com/baba/is/api/handler"
+	"github.com/baba/is/you/common"
+)
+`))
+	opener.MockAt("baba/services/api/handler/handler.go", NewFakeFile(`
+package handler
+
+import "github.com/baba/is/you/common"
+`))
+
+	modules := []info.Module{
+		{Path: testModuleName, Dir: "."},
+		{Path: "github.com/baba/is/api", Dir: "services/api"},
+	}
+	r := info.NewPackageReader(dirReader, opener, emptyConfig)
+
+	packages, errRead := r.ReadModules("baba", modules)
+
+	require.NoError(t, errRead)
+	require.Len(t, packages, 3)
+
+	byPath := make(map[string]info.PackageInfo, len(packages))
+	for _, pkg := range packages {
+		byPath[pkg.Path] = pkg
+	}
+
+	require.Equal(t, modules[0], byPath["common"].Module)
+	require.Empty(t, byPath["common"].Dependencies)
+
+	require.Equal(t, modules[1], byPath["services/api"].Module)
+	require.ElementsMatch(t, []string{"common", "services/api/handler"}, byPath["services/api"].Dependencies)
+
+	require.Equal(t, modules[1], byPath["services/api/handler"].Module)
+	require.Equal(t, []string{"common"}, byPath["services/api/handler"].Dependencies)
+	require.Empty(t, byPath["services/api/handler"].ExternalDependencies)
+}
+
+func TestPackageReader_ReadRecursively_Target_OnlyMatchingFiles(t *testing.T) {
+	newOpener := func() *MockedFileOpener {
+		opener := NewFileOpener()
+		opener.MockAt("baba/service/service.go", NewFakeFile(`package service
+
+import "github.com/baba/is/you/common"
+`))
+		opener.MockAt("baba/service/service_windows.go", NewFakeFile(`package service
+
+import "github.com/baba/is/you/winapi"
+`))
+		opener.MockAt("baba/service/feature.go", NewFakeFile(`//go:build flag
+
+package service
+
+import "github.com/baba/is/you/flag"
+`))
+		return opener
+	}
+
+	dirReader := NewDirReader()
+	dirReader.MockAt("baba", []models.DirEntry{DirEntry{name: "service", isDir: true}})
+	dirReader.MockAt("baba/service", []models.DirEntry{
+		DirEntry{name: "service.go"},
+		DirEntry{name: "service_windows.go"},
+		DirEntry{name: "feature.go"},
+	})
+
+	testCases := []struct {
+		name                 string
+		target               *info.Target
+		expectedDependencies []string
+		expectedIgnored      []string
+	}{
+		{
+			name:                 "no target should read all files",
+			expectedDependencies: []string{"common", "winapi", "flag"},
+			expectedIgnored:      []string{},
+		},
+		{
+			name:                 "linux target should ignore windows files and unset tags",
+			target:               &info.Target{Name: "linux", GOOS: "linux", GOARCH: "amd64"},
+			expectedDependencies: []string{"common"},
+			expectedIgnored:      []string{"service/service_windows.go", "service/feature.go"},
+		},
+		{
+			name:                 "windows target with tags should read all files",
+			target:               &info.Target{Name: "windows", GOOS: "windows", GOARCH: "amd64", Tags: []string{"flag"}},
+			expectedDependencies: []string{"common", "winapi", "flag"},
+			expectedIgnored:      []string{},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			r := info.NewPackageReader(dirReader, newOpener(), emptyConfig)
+			if c.target != nil {
+				r = r.WithTarget(*c.target)
+			}
+
+			packages, errRead := r.ReadRecursively("baba", testModuleName)
+
+			require.NoError(t, errRead)
+			require.Len(t, packages, 1)
+
+			require.ElementsMatch(t, c.expectedDependencies, packages[0].Dependencies)
+			require.ElementsMatch(t, c.expectedIgnored, packages[0].IgnoredFiles)
+			require.Len(t, packages[0].Files, 3-len(c.expectedIgnored))
+			require.False(t, packages[0].IsExcluded())
+		})
+	}
+}
+
+func TestPackageReader_ReadRecursively_NameAndOtherFiles(t *testing.T) {
+	dirReader := NewDirReader()
+	dirReader.MockAt("baba", []models.DirEntry{
+		DirEntry{
+			name:  "cmd",
+			isDir: true,
+		},
+	})
+	dirReader.MockAt("baba/cmd", []models.DirEntry{
+		DirEntry{
+			name: "main_test.go",
+		},
+		DirEntry{
+			name: "main.go",
+		},
+		DirEntry{
+			name: "Dockerfile",
+		},
+	})
+
+	opener := NewFileOpener()
+	opener.MockAt("baba/cmd/main_test.go", NewFakeFile("package main_test").CanClose(false))
+	opener.MockAt("baba/cmd/main.go", NewFakeFile("package main").CanClose(false))
+
+	r := info.NewPackageReader(dirReader, opener, emptyConfig)
+
+	packages, errRead := r.ReadRecursively("baba", testModuleName)
+
+	require.NoError(t, errRead)
+	require.Len(t, packages, 1)
+
+	require.Equal(t, "main", packages[0].Name)
+	require.True(t, packages[0].IsMain())
+	require.Equal(t, []string{"cmd/Dockerfile"}, packages[0].OtherFiles)
+}
+
+func TestPackageReader_ReadIgnoredDirs(t *testing.T) {
+	dirReader := NewDirReader()
+	dirReader.MockAt("baba", []models.DirEntry{
+		DirEntry{
+			name:  "is",
+			isDir: true,
+		},
+		DirEntry{
+			name:  "build",
+			isDir: true,
+		},
+		DirEntry{
+			name:  "vendor",
+			isDir: true,
+		},
+		DirEntry{
+			name:  ".git",
+			isDir: true,
+		},
+		DirEntry{
+			name: "go.mod",
+		},
+	})
+	dirReader.MockAt("baba/is", []models.DirEntry{
+		DirEntry{
+			name:  "mocks",
+			isDir: true,
+		},
+		DirEntry{
+			name:  "you",
+			isDir: true,
+		},
+	})
+	dirReader.MockAt("baba/is/you", []models.DirEntry{})
+
+	r := info.NewPackageReader(dirReader, NewFileOpener(), info.NewConfig("build$", "vendor$", ".*/mocks$"))
+
+	dirs, errRead := r.ReadIgnoredDirs("baba")
+
+	require.NoError(t, errRead)
+	require.ElementsMatch(t, []string{"build", "is/mocks"}, dirs)
+}
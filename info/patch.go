@@ -0,0 +1,323 @@
+package info
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	devNull = "/dev/null"
+)
+
+var (
+	errInvalidPatchFormat = errors.New("invalid patch format")
+)
+
+type (
+	patchParser struct {
+		lines   []string
+		index   int
+		result  []ChangeInfo
+		current *patchFile
+	}
+
+	patchFile struct {
+		change   ChangeInfo
+		oldName  string
+		newName  string
+		hasHunks bool
+	}
+)
+
+func ParseUnifiedDiff(patchContent string) ([]ChangeInfo, error) {
+	p := patchParser{
+		lines:  strings.Split(patchContent, "\n"),
+		result: make([]ChangeInfo, 0),
+	}
+
+	if errParse := p.parse(); errParse != nil {
+		return nil, errParse
+	}
+
+	return p.result, nil
+}
+
+func (p *patchParser) parse() error {
+	for ; p.index < len(p.lines); p.index++ {
+		line := p.lines[p.index]
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			if errFlush := p.flush(); errFlush != nil {
+				return errFlush
+			}
+
+			oldName, newName := splitGitHeader(strings.TrimPrefix(line, "diff --git "))
+			p.current = &patchFile{oldName: oldName, newName: newName}
+		case strings.HasPrefix(line, "--- "):
+			if p.current == nil || p.current.hasHunks {
+				if errFlush := p.flush(); errFlush != nil {
+					return errFlush
+				}
+
+				p.current = &patchFile{}
+			}
+
+			p.current.oldName = parsePatchPath(strings.TrimPrefix(line, "--- "))
+		case p.current == nil:
+			continue // preamble, e.g. commit message of a formatted patch
+		case strings.HasPrefix(line, "+++ "):
+			p.current.newName = parsePatchPath(strings.TrimPrefix(line, "+++ "))
+		case strings.HasPrefix(line, "new file mode"):
+			p.current.change.Status = StatusAdded
+		case strings.HasPrefix(line, "deleted file mode"):
+			p.current.change.Status = StatusDeleted
+		case strings.HasPrefix(line, "rename from "):
+			p.current.change.Status = StatusRenamed
+			p.current.oldName = unquotePath(strings.TrimPrefix(line, "rename from "))
+		case strings.HasPrefix(line, "rename to "):
+			p.current.newName = unquotePath(strings.TrimPrefix(line, "rename to "))
+		case strings.HasPrefix(line, "copy from "):
+			p.current.change.Status = StatusCopied
+			p.current.oldName = unquotePath(strings.TrimPrefix(line, "copy from "))
+		case strings.HasPrefix(line, "copy to "):
+			p.current.newName = unquotePath(strings.TrimPrefix(line, "copy to "))
+		case strings.HasPrefix(line, "@@ "):
+			if errHunk := p.parseHunk(line); errHunk != nil {
+				return errHunk
+			}
+		}
+	}
+
+	return p.flush()
+}
+
+func (p *patchParser) parseHunk(header string) error {
+	oldStart, oldLines, newStart, newLines, ok := parseHunkHeader(header)
+	if ok == false {
+		return errInvalidPatchFormat
+	}
+
+	p.current.hasHunks = true
+	oldPos, newPos := oldStart, newStart
+	if oldLines == 0 {
+		oldPos++
+	}
+	if newLines == 0 {
+		newPos++
+	}
+
+	var block *Hunk
+	closeBlock := func() {
+		if block == nil {
+			return
+		}
+
+		if block.OldLines == 0 {
+			block.OldStart = oldPos - 1
+		}
+		if block.NewLines == 0 {
+			block.NewStart = newPos - 1
+		}
+
+		p.current.change.Hunks = append(p.current.change.Hunks, *block)
+		block = nil
+	}
+
+	for oldLines > 0 || newLines > 0 {
+		p.index++
+		if p.index >= len(p.lines) {
+			return errInvalidPatchFormat
+		}
+
+		line := p.lines[p.index]
+		if line == "" {
+			line = " " // some tools strip the trailing space of empty context lines
+		}
+
+		switch line[0] {
+		case ' ':
+			closeBlock()
+			oldPos++
+			newPos++
+			oldLines--
+			newLines--
+		case '-':
+			if block == nil {
+				block = &Hunk{OldStart: oldPos, NewStart: newPos}
+			}
+			block.OldLines++
+			oldPos++
+			oldLines--
+		case '+':
+			if block == nil {
+				block = &Hunk{OldStart: oldPos, NewStart: newPos}
+			}
+			block.NewLines++
+			newPos++
+			newLines--
+		case '\\':
+			continue // no newline at end of file
+		default:
+			return errInvalidPatchFormat
+		}
+	}
+
+	if oldLines < 0 || newLines < 0 {
+		return errInvalidPatchFormat
+	}
+
+	closeBlock()
+	return nil
+}
+
+func (p *patchParser) flush() error {
+	if p.current == nil {
+		return nil
+	}
+
+	file := p.current
+	p.current = nil
+
+	change := file.change
+	switch {
+	case file.oldName == devNull:
+		change.Status = StatusAdded
+	case file.newName == devNull:
+		change.Status = StatusDeleted
+	case change.Status == 0:
+		change.Status = StatusModified
+	}
+
+	switch change.Status {
+	case StatusDeleted:
+		change.Path = file.oldName
+	case StatusRenamed, StatusCopied:
+		change.OldPath = file.oldName
+		change.Path = file.newName
+	default:
+		change.Path = file.newName
+	}
+
+	if change.Path == "" || change.Path == devNull || change.HasOldPath() && change.OldPath == "" {
+		return errInvalidPatchFormat
+	}
+
+	p.result = append(p.result, change)
+	return nil
+}
+
+func parseHunkHeader(header string) (int, int, int, int, bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 4 || fields[3] != "@@" ||
+		strings.HasPrefix(fields[1], "-") == false || strings.HasPrefix(fields[2], "+") == false {
+		return 0, 0, 0, 0, false
+	}
+
+	oldStart, oldLines, okOld := parseHunkRange(fields[1][1:])
+	newStart, newLines, okNew := parseHunkRange(fields[2][1:])
+
+	return oldStart, oldLines, newStart, newLines, okOld && okNew
+}
+
+func parseHunkRange(value string) (int, int, bool) {
+	start, count, hasCount := strings.Cut(value, ",")
+
+	startNum, errStart := strconv.Atoi(start)
+	if errStart != nil || startNum < 0 {
+		return 0, 0, false
+	}
+
+	if hasCount == false {
+		return startNum, 1, true
+	}
+
+	countNum, errCount := strconv.Atoi(count)
+	if errCount != nil || countNum < 0 {
+		return 0, 0, false
+	}
+
+	return startNum, countNum, true
+}
+
+func parsePatchPath(value string) string {
+	if i := strings.IndexByte(value, '\t'); i >= 0 {
+		value = value[:i] // timestamp of non git diffs
+	}
+
+	path := unquotePath(strings.TrimRight(value, " "))
+	if path == devNull {
+		return path
+	}
+
+	return stripPatchPrefix(path)
+}
+
+func splitGitHeader(header string) (string, string) {
+	if strings.HasPrefix(header, "\"") {
+		oldName, rest, ok := cutQuoted(header)
+		if ok == false {
+			return "", ""
+		}
+
+		return stripPatchPrefix(oldName), stripPatchPrefix(unquotePath(strings.TrimPrefix(rest, " ")))
+	}
+
+	// prefer the split in which both sides point to the same path
+	for i := strings.Index(header, " b/"); i >= 0; {
+		oldName, newName := header[:i], header[i+1:]
+		if stripPatchPrefix(oldName) == stripPatchPrefix(newName) {
+			return stripPatchPrefix(oldName), stripPatchPrefix(newName)
+		}
+
+		next := strings.Index(header[i+1:], " b/")
+		if next < 0 {
+			break
+		}
+
+		i += next + 1
+	}
+
+	oldName, newName, _ := strings.Cut(header, " b/")
+	return stripPatchPrefix(oldName), newName
+}
+
+func cutQuoted(value string) (string, string, bool) {
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case '"':
+			unquoted, errUnquote := strconv.Unquote(value[:i+1])
+			if errUnquote != nil {
+				return "", "", false
+			}
+
+			return unquoted, value[i+1:], true
+		}
+	}
+
+	return "", "", false
+}
+
+func unquotePath(path string) string {
+	if strings.HasPrefix(path, "\"") == false {
+		return path
+	}
+
+	unquoted, errUnquote := strconv.Unquote(path)
+	if errUnquote != nil {
+		return path
+	}
+
+	return unquoted
+}
+
+func stripPatchPrefix(path string) string {
+	if strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "b/") {
+		return path[2:]
+	}
+
+	return path
+}
@@ -0,0 +1,197 @@
+package info_test
+
+import (
+	"github.com/go-lean/bevaluate/info"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseUnifiedDiff_BadContent_Error(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "bad hunk header should be invalid",
+			content: "--- a/baba.go\n+++ b/baba.go\n@@ baba is you @@\n",
+		},
+		{
+			name:    "truncated hunk should be invalid",
+			content: "--- a/baba.go\n+++ b/baba.go\n@@ -1,2 +1,2 @@\n-baba\n",
+		},
+		{
+			name:    "unexpected hunk line should be invalid",
+			content: "--- a/baba.go\n+++ b/baba.go\n@@ -1 +1 @@\n?baba\n",
+		},
+		{
+			name:    "missing paths should be invalid",
+			content: "--- /dev/null\n+++ /dev/null\n",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			changes, errParse := info.ParseUnifiedDiff(c.content)
+
+			require.Error(t, errParse)
+			require.Contains(t, errParse.Error(), "invalid")
+			require.Empty(t, changes)
+		})
+	}
+}
+
+func TestParseUnifiedDiff_EmptyContent_OK(t *testing.T) {
+	changes, errParse := info.ParseUnifiedDiff("")
+
+	require.NoError(t, errParse)
+	require.Empty(t, changes)
+}
+
+func TestParseUnifiedDiff_ZeroContext_OK(t *testing.T) {
+	patch := `diff --git a/baba/baba.go b/baba/baba.go
+index 1b2c3d4..5e6f7a8 100644
+--- a/baba/baba.go
++++ b/baba/baba.go
+@@ -3 +3,2 @@ package baba
+-var is = "you"
++var is = "flag"
++var win = true
+@@ -10,2 +11,0 @@ func baba() {
+-	return
+-}
+@@ -20,0 +20 @@ func is() {
++	// you
+diff --git a/is/is.go b/is/is.go
+deleted file mode 100644
+index 1b2c3d4..0000000
+--- a/is/is.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package is
+diff --git a/you/you.go b/you/you.go
+new file mode 100644
+index 0000000..1b2c3d4
+--- /dev/null
++++ b/you/you.go
+@@ -0,0 +1,2 @@
++package you
++
+\ No newline at end of file
+`
+
+	changes, errParse := info.ParseUnifiedDiff(patch)
+
+	require.NoError(t, errParse)
+	expected := []info.ChangeInfo{
+		{
+			Status: info.StatusModified,
+			Path:   "baba/baba.go",
+			Hunks: []info.Hunk{
+				{OldStart: 3, OldLines: 1, NewStart: 3, NewLines: 2},
+				{OldStart: 10, OldLines: 2, NewStart: 11, NewLines: 0},
+				{OldStart: 20, OldLines: 0, NewStart: 20, NewLines: 1},
+			},
+		},
+		{
+			Status: info.StatusDeleted,
+			Path:   "is/is.go",
+			Hunks:  []info.Hunk{{OldStart: 1, OldLines: 1, NewStart: 0, NewLines: 0}},
+		},
+		{
+			Status: info.StatusAdded,
+			Path:   "you/you.go",
+			Hunks:  []info.Hunk{{OldStart: 0, OldLines: 0, NewStart: 1, NewLines: 2}},
+		},
+	}
+	require.Equal(t, expected, changes)
+}
+
+func TestParseUnifiedDiff_WithContext_SplitsChangedBlocks(t *testing.T) {
+	patch := `--- baba.go	2023-01-01 00:00:00
++++ baba.go	2023-01-02 00:00:00
+@@ -1,7 +1,7 @@
+ package baba
+
+-var is = "you"
++var is = "flag"
+
+ func baba() {
++	win()
+-	lose()
+ }
+`
+
+	changes, errParse := info.ParseUnifiedDiff(patch)
+
+	require.NoError(t, errParse)
+	expected := []info.ChangeInfo{
+		{
+			Status: info.StatusModified,
+			Path:   "baba.go",
+			Hunks: []info.Hunk{
+				{OldStart: 3, OldLines: 1, NewStart: 3, NewLines: 1},
+				{OldStart: 6, OldLines: 1, NewStart: 6, NewLines: 1},
+			},
+		},
+	}
+	require.Equal(t, expected, changes)
+}
+
+func TestParseUnifiedDiff_RenamedCopiedAndBinary_OK(t *testing.T) {
+	patch := `From 1b2c3d4 Mon Sep 17 00:00:00 2001
+Subject: [PATCH] baba is you
+
+---
+diff --git a/old dir/baba.go b/new dir/baba.go
+similarity index 90%
+rename from old dir/baba.go
+rename to new dir/baba.go
+index 1b2c3d4..5e6f7a8 100644
+--- a/old dir/baba.go
++++ b/new dir/baba.go
+@@ -1 +1 @@
+-package baba
++package is
+diff --git a/baba/is.go b/you/is.go
+similarity index 75%
+copy from baba/is.go
+copy to you/is.go
+diff --git a/assets/logo.png b/assets/logo.png
+index 1b2c3d4..5e6f7a8 100644
+Binary files a/assets/logo.png and b/assets/logo.png differ
+`
+
+	changes, errParse := info.ParseUnifiedDiff(patch)
+
+	require.NoError(t, errParse)
+	expected := []info.ChangeInfo{
+		{
+			Status:  info.StatusRenamed,
+			OldPath: "old dir/baba.go",
+			Path:    "new dir/baba.go",
+			Hunks:   []info.Hunk{{OldStart: 1, OldLines: 1, NewStart: 1, NewLines: 1}},
+		},
+		{
+			Status:  info.StatusCopied,
+			OldPath: "baba/is.go",
+			Path:    "you/is.go",
+		},
+		{
+			Status: info.StatusModified,
+			Path:   "assets/logo.png",
+		},
+	}
+	require.Equal(t, expected, changes)
+}
+
+func TestParseUnifiedDiff_EmptyNewFile_UsesGitHeader(t *testing.T) {
+	patch := `diff --git a/baba/empty.go b/baba/empty.go
+new file mode 100644
+index 0000000..e69de29
+`
+
+	changes, errParse := info.ParseUnifiedDiff(patch)
+
+	require.NoError(t, errParse)
+	require.Equal(t, []info.ChangeInfo{{Status: info.StatusAdded, Path: "baba/empty.go"}}, changes)
+}
@@ -0,0 +1,381 @@
+package info
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type (
+	Symbols map[string]struct{}
+
+	SymbolImpact struct {
+		Referenced     bool
+		TestReferenced bool
+		Symbols        Symbols
+	}
+
+	SymbolAnalyzer struct {
+		versions  SourceVersions
+		dirReader DirReader
+		root      string
+		modules   []Module
+		cache     map[string]*packageSource
+	}
+
+	packageSource struct {
+		name      string
+		files     []*ast.File
+		testFiles []*ast.File
+	}
+
+	declaration struct {
+		names        []string
+		node         ast.Node
+		isMethod     bool
+		isSideEffect bool
+	}
+)
+
+func NewSymbolAnalyzer(versions SourceVersions, dirReader DirReader, root string, modules []Module) SymbolAnalyzer {
+	return SymbolAnalyzer{
+		versions:  versions,
+		dirReader: dirReader,
+		root:      root,
+		modules:   modules,
+		cache:     make(map[string]*packageSource),
+	}
+}
+
+func (a SymbolAnalyzer) ChangedSymbols(change ChangeInfo) (Symbols, bool) {
+	if change.Status != StatusModified || strings.HasSuffix(change.Path, ".go") == false ||
+		strings.HasSuffix(change.Path, "_test.go") {
+		return nil, false
+	}
+
+	oldSource, errOld := a.versions.Old(change.Path)
+	if errOld != nil {
+		return nil, false
+	}
+
+	newSource, errNew := a.versions.New(change.Path)
+	if errNew != nil {
+		return nil, false
+	}
+
+	oldFile, errParseOld := normalizeSource(change.Path, oldSource)
+	if errParseOld != nil {
+		return nil, false
+	}
+
+	newFile, errParseNew := normalizeSource(change.Path, newSource)
+	if errParseNew != nil || oldFile.usesCgo || newFile.usesCgo ||
+		oldFile.packageName != newFile.packageName ||
+		equalStrings(blankImports(oldFile.imports), blankImports(newFile.imports)) == false ||
+		equalStrings(oldFile.directives, newFile.directives) == false {
+		return nil, false
+	}
+
+	seeds := make(Symbols)
+	for _, decl := range changedDecls(oldFile, newFile) {
+		if decl.isMethod || isSideEffectDecl(decl.names) {
+			return nil, false // methods, init functions and blank declarations can not be tracked by name
+		}
+
+		for _, name := range decl.names {
+			seeds[name] = struct{}{}
+		}
+	}
+
+	pkg, errRead := a.readPackage(filepath.Dir(change.Path))
+	if errRead != nil {
+		return nil, false
+	}
+
+	affected, ok := affectedDeclarations(pkg.files, func(decl declaration) bool {
+		return referencesNames(decl.node, seeds)
+	})
+	if ok == false {
+		return nil, false
+	}
+
+	result := exportedNames(affected)
+	for name := range seeds {
+		if ast.IsExported(name) {
+			result[name] = struct{}{}
+		}
+	}
+
+	return result, true
+}
+
+func (a SymbolAnalyzer) Impact(dependant, dependency string, symbols Symbols) (SymbolImpact, bool) {
+	dependencyPkg, errDependency := a.readPackage(dependency)
+	if errDependency != nil {
+		return SymbolImpact{}, false
+	}
+
+	dependantPkg, errDependant := a.readPackage(dependant)
+	if errDependant != nil {
+		return SymbolImpact{}, false
+	}
+
+	module, ok := ModuleOf(dependency, a.modules)
+	if ok == false {
+		return SymbolImpact{}, false
+	}
+
+	importPath := module.ImportPath(dependency)
+	result := SymbolImpact{Symbols: make(Symbols)}
+
+	for _, file := range dependantPkg.testFiles {
+		referenced, ok := fileReferencesSymbols(file, importPath, dependencyPkg.name, symbols)
+		if ok == false {
+			return SymbolImpact{}, false
+		}
+
+		result.TestReferenced = result.TestReferenced || referenced
+	}
+
+	referencing := make(map[ast.Node]struct{})
+	for _, file := range dependantPkg.files {
+		localName, imported := importName(file, importPath, dependencyPkg.name)
+		if imported == false {
+			continue
+		}
+
+		if localName == "." {
+			return SymbolImpact{}, false
+		}
+
+		for _, decl := range fileDeclarations(file) {
+			if referencesSelectors(decl.node, localName, symbols) {
+				referencing[decl.node] = struct{}{}
+			}
+		}
+	}
+
+	if len(referencing) == 0 {
+		return result, true
+	}
+
+	affected, ok := affectedDeclarations(dependantPkg.files, func(decl declaration) bool {
+		_, isReferencing := referencing[decl.node]
+		return isReferencing
+	})
+	if ok == false {
+		return SymbolImpact{}, false
+	}
+
+	result.Referenced = true
+	result.Symbols = exportedNames(affected)
+
+	return result, true
+}
+
+func (a SymbolAnalyzer) readPackage(dir string) (*packageSource, error) {
+	if pkg, ok := a.cache[dir]; ok {
+		return pkg, nil
+	}
+
+	entries, errRead := a.dirReader.Read(filepath.Join(a.root, dir))
+	if errRead != nil {
+		return nil, fmt.Errorf("could not read package dir: %w", errRead)
+	}
+
+	pkg := &packageSource{}
+	for _, entry := range entries {
+		filePath := filepath.Join(dir, entry.Name())
+		if entry.IsDir() || strings.HasSuffix(filePath, ".go") == false {
+			continue
+		}
+
+		source, errSource := a.versions.New(filePath)
+		if errSource != nil {
+			return nil, fmt.Errorf("could not read package file: %w", errSource)
+		}
+
+		parsedFile, errParse := parser.ParseFile(token.NewFileSet(), filePath, source, parser.SkipObjectResolution)
+		if errParse != nil {
+			return nil, fmt.Errorf("could not parse package file: %w", errParse)
+		}
+
+		if strings.HasSuffix(filePath, "_test.go") {
+			pkg.testFiles = append(pkg.testFiles, parsedFile)
+			continue
+		}
+
+		pkg.name = parsedFile.Name.Name
+		pkg.files = append(pkg.files, parsedFile)
+	}
+
+	a.cache[dir] = pkg
+	return pkg, nil
+}
+
+func affectedDeclarations(files []*ast.File, isSeed func(declaration) bool) ([]declaration, bool) {
+	decls := make([]declaration, 0)
+	for _, file := range files {
+		decls = append(decls, fileDeclarations(file)...)
+	}
+
+	affected := make([]declaration, 0)
+	affectedNames := make(Symbols)
+	isAffected := make([]bool, len(decls))
+
+	for changed := true; changed; {
+		changed = false
+
+		for i, decl := range decls {
+			if isAffected[i] || isSeed(decl) == false && referencesNames(decl.node, affectedNames) == false {
+				continue
+			}
+
+			if decl.isMethod || decl.isSideEffect {
+				return nil, false
+			}
+
+			isAffected[i] = true
+			changed = true
+			affected = append(affected, decl)
+
+			for _, name := range decl.names {
+				affectedNames[name] = struct{}{}
+			}
+		}
+	}
+
+	return affected, true
+}
+
+func fileDeclarations(file *ast.File) []declaration {
+	result := make([]declaration, 0, len(file.Decls))
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			result = append(result, declaration{
+				names:        []string{d.Name.Name},
+				node:         d,
+				isMethod:     d.Recv != nil,
+				isSideEffect: d.Recv == nil && d.Name.Name == "init",
+			})
+		case *ast.GenDecl:
+			if d.Tok == token.IMPORT {
+				continue
+			}
+
+			for _, spec := range d.Specs {
+				names := specNames(spec)
+				result = append(result, declaration{
+					names:        names,
+					node:         spec,
+					isSideEffect: isSideEffectDecl(names),
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+func fileReferencesSymbols(file *ast.File, importPath, packageName string, symbols Symbols) (bool, bool) {
+	localName, imported := importName(file, importPath, packageName)
+	if imported == false {
+		return false, true
+	}
+
+	if localName == "." {
+		return false, false
+	}
+
+	return referencesSelectors(file, localName, symbols), true
+}
+
+func importName(file *ast.File, importPath, packageName string) (string, bool) {
+	for _, imp := range file.Imports {
+		impPath, errUnquote := strconv.Unquote(imp.Path.Value)
+		if errUnquote != nil || impPath != importPath {
+			continue
+		}
+
+		if imp.Name != nil {
+			return imp.Name.Name, imp.Name.Name != "_"
+		}
+
+		return packageName, true
+	}
+
+	return "", false
+}
+
+func referencesSelectors(node ast.Node, localName string, symbols Symbols) bool {
+	referenced := false
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		if referenced {
+			return false
+		}
+
+		selector, ok := n.(*ast.SelectorExpr)
+		if ok == false {
+			return true
+		}
+
+		if ident, isIdent := selector.X.(*ast.Ident); isIdent && ident.Name == localName {
+			_, referenced = symbols[selector.Sel.Name]
+		}
+
+		return true
+	})
+
+	return referenced
+}
+
+func referencesNames(node ast.Node, names Symbols) bool {
+	if len(names) == 0 {
+		return false
+	}
+
+	return isReferenced(node, names, nil)
+}
+
+func exportedNames(decls []declaration) Symbols {
+	result := make(Symbols)
+
+	for _, decl := range decls {
+		for _, name := range decl.names {
+			if ast.IsExported(name) {
+				result[name] = struct{}{}
+			}
+		}
+	}
+
+	return result
+}
+
+func isSideEffectDecl(names []string) bool {
+	for _, name := range names {
+		if name == "_" || name == "init" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func blankImports(imports []string) []string {
+	result := make([]string, 0, len(imports))
+
+	for _, imp := range imports {
+		if strings.HasPrefix(imp, "_ ") || strings.HasPrefix(imp, ". ") {
+			result = append(result, imp)
+		}
+	}
+
+	return result
+}
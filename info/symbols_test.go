@@ -0,0 +1,255 @@
+package info_test
+
+import (
+	"github.com/go-lean/bevaluate/info"
+	"github.com/go-lean/bevaluate/models"
+	"github.com/go-lean/bevaluate/util"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const symbolsBaseSource = `package rules
+
+func Is(you string) string {
+	return format(you)
+}
+
+func Win() bool {
+	return true
+}
+
+func format(value string) string {
+	return "baba is " + value
+}
+
+type Rule struct{}
+
+func (Rule) Apply() {}
+`
+
+func newSymbolAnalyzer(versions *FakeVersions, packages map[string][]string) info.SymbolAnalyzer {
+	dirReader := NewDirReader()
+	for dir, files := range packages {
+		entries := make([]models.DirEntry, 0, len(files))
+		for _, file := range files {
+			entries = append(entries, DirEntry{name: file})
+		}
+
+		dirReader.MockAt("root/"+dir, entries)
+	}
+
+	return info.NewSymbolAnalyzer(versions, dirReader, "root", []info.Module{{Path: testModuleName, Dir: "."}})
+}
+
+func TestSymbolAnalyzer_ChangedSymbols_NotTrackable_NotOk(t *testing.T) {
+	testCases := []struct {
+		name      string
+		change    info.ChangeInfo
+		newSource string
+	}{
+		{
+			name:   "added file should not be ok",
+			change: info.ChangeInfo{Status: info.StatusAdded, Path: "rules/rules.go"},
+		},
+		{
+			name:   "test file should not be ok",
+			change: info.ChangeInfo{Status: info.StatusModified, Path: "rules/rules_test.go"},
+		},
+		{
+			name:      "changed method should not be ok",
+			change:    info.ChangeInfo{Status: info.StatusModified, Path: "rules/rules.go"},
+			newSource: symbolsBaseSource + "\nfunc (Rule) Undo() {}\n",
+		},
+		{
+			name:      "added init should not be ok",
+			change:    info.ChangeInfo{Status: info.StatusModified, Path: "rules/rules.go"},
+			newSource: symbolsBaseSource + "\nfunc init() {}\n",
+		},
+		{
+			name:      "helper used by init should not be ok",
+			change:    info.ChangeInfo{Status: info.StatusModified, Path: "rules/rules.go"},
+			newSource: symbolsBaseSource + "\nfunc init() { _ = format(\"flag\") }\n",
+		},
+		{
+			name:      "bad code should not be ok",
+			change:    info.ChangeInfo{Status: info.StatusModified, Path: "rules/rules.go"},
+			newSource: "bad go code",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			versions := NewFakeVersions().MockAt("rules/rules.go", symbolsBaseSource, c.newSource)
+			analyzer := newSymbolAnalyzer(versions, map[string][]string{"rules": {"rules.go"}})
+
+			symbols, ok := analyzer.ChangedSymbols(c.change)
+
+			require.False(t, ok)
+			require.Empty(t, symbols)
+		})
+	}
+}
+
+func TestSymbolAnalyzer_ChangedSymbols_OK(t *testing.T) {
+	testCases := []struct {
+		name      string
+		newSource string
+		expected  []string
+	}{
+		{
+			name:      "exported function should be changed",
+			newSource: replace(symbolsBaseSource, "return true", "return false"),
+			expected:  []string{"Win"},
+		},
+		{
+			name:      "unexported helper should change its exported users",
+			newSource: replace(symbolsBaseSource, `"baba is "`, `"baba is not "`),
+			expected:  []string{"Is"},
+		},
+		{
+			name:      "removed exported function should be changed",
+			newSource: replace(symbolsBaseSource, "func Win() bool {\n\treturn true\n}\n", ""),
+			expected:  []string{"Win"},
+		},
+		{
+			name:      "unused unexported function should change nothing",
+			newSource: symbolsBaseSource + "\nfunc flag() {}\n",
+			expected:  []string{},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			versions := NewFakeVersions().MockAt("rules/rules.go", symbolsBaseSource, c.newSource)
+			analyzer := newSymbolAnalyzer(versions, map[string][]string{"rules": {"rules.go"}})
+
+			symbols, ok := analyzer.ChangedSymbols(info.ChangeInfo{Status: info.StatusModified, Path: "rules/rules.go"})
+
+			require.True(t, ok)
+			require.ElementsMatch(t, c.expected, util.MapKeys(symbols))
+		})
+	}
+}
+
+func TestSymbolAnalyzer_Impact(t *testing.T) {
+	testCases := []struct {
+		name             string
+		source           string
+		testSource       string
+		expectedOk       bool
+		expectedRef      bool
+		expectedTestRef  bool
+		expectedExported []string
+	}{
+		{
+			name: "not referencing should not be affected",
+			source: `package service
+
+import "github.com/baba/is/you/rules"
+
+func Start() bool { return rules.Win() }
+`,
+			testSource:       "package service\n",
+			expectedOk:       true,
+			expectedExported: []string{},
+		},
+		{
+			name: "referencing should affect exported users",
+			source: `package service
+
+import r "github.com/baba/is/you/rules"
+
+type Alias = r.Rule
+
+func Start() string { return greet() }
+
+func greet() string { return r.Is("you") }
+`,
+			testSource:       "package service\n",
+			expectedOk:       true,
+			expectedRef:      true,
+			expectedExported: []string{"Start"},
+		},
+		{
+			name: "referencing only in tests should only affect tests",
+			source: `package service
+
+import _ "github.com/baba/is/you/rules"
+`,
+			testSource: `package service_test
+
+import "github.com/baba/is/you/rules"
+
+var rule = rules.Is("you")
+`,
+			expectedOk:       true,
+			expectedTestRef:  true,
+			expectedExported: []string{},
+		},
+		{
+			name: "dot import should not be ok",
+			source: `package service
+
+import . "github.com/baba/is/you/rules"
+
+func Start() string { return Is("you") }
+`,
+			testSource: "package service\n",
+		},
+		{
+			name: "referencing from a method should not be ok",
+			source: `package service
+
+import "github.com/baba/is/you/rules"
+
+type Service struct{}
+
+func (Service) Start() string { return rules.Is("you") }
+`,
+			testSource: "package service\n",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			versions := NewFakeVersions().
+				MockAt("rules/rules.go", symbolsBaseSource, symbolsBaseSource).
+				MockAt("service/service.go", c.source, c.source).
+				MockAt("service/service_test.go", c.testSource, c.testSource)
+			analyzer := newSymbolAnalyzer(versions, map[string][]string{
+				"rules":   {"rules.go"},
+				"service": {"service.go", "service_test.go"},
+			})
+
+			impact, ok := analyzer.Impact("service", "rules", info.Symbols{"Is": {}})
+
+			require.Equal(t, c.expectedOk, ok)
+			if ok == false {
+				return
+			}
+
+			require.Equal(t, c.expectedRef, impact.Referenced)
+			require.Equal(t, c.expectedTestRef, impact.TestReferenced)
+			require.ElementsMatch(t, c.expectedExported, util.MapKeys(impact.Symbols))
+		})
+	}
+}
+
+func TestSymbolAnalyzer_Impact_ReadError_NotOk(t *testing.T) {
+	analyzer := newSymbolAnalyzer(NewFakeVersions(), map[string][]string{"rules": {"rules.go"}})
+
+	impact, ok := analyzer.Impact("service", "rules", info.Symbols{"Is": {}})
+
+	require.False(t, ok)
+	require.Empty(t, impact.Symbols)
+}
+
+func replace(source, old, new string) string {
+	result := strings.Replace(source, old, new, 1)
+	if result == source {
+		panic("nothing replaced: " + old)
+	}
+
+	return result
+}
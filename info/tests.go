@@ -0,0 +1,208 @@
+package info
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type (
+	TestFunctionSelector struct {
+		versions SourceVersions
+	}
+
+	testFunction struct {
+		name  string
+		lines LineRange
+	}
+)
+
+var (
+	testFunctionPrefixes = []string{"Test", "Fuzz", "Example"}
+)
+
+func NewTestFunctionSelector(versions SourceVersions) TestFunctionSelector {
+	return TestFunctionSelector{versions: versions}
+}
+
+// ChangedTests returns the test, fuzz and example functions of a test file whose lines were changed.
+// It is not ok when anything else was changed, like helpers, TestMain or build constraints, since
+// those could affect every test of the package.
+func (s TestFunctionSelector) ChangedTests(change ChangeInfo) ([]string, bool) {
+	if strings.HasSuffix(change.Path, "_test.go") == false || len(change.Hunks) == 0 ||
+		change.Status != StatusModified && change.Status != StatusAdded {
+		return nil, false
+	}
+
+	source, errSource := s.versions.New(change.Path)
+	if errSource != nil {
+		return nil, false
+	}
+
+	fileSet := token.NewFileSet()
+	file, errParse := parser.ParseFile(fileSet, change.Path, source, parser.ParseComments)
+	if errParse != nil {
+		return nil, false
+	}
+
+	functions := testFunctions(fileSet, file)
+	lines := strings.Split(string(source), "\n")
+	neutral := neutralLines(fileSet, file, lines, change.Status == StatusAdded)
+	changed := make(map[string]struct{}, len(change.Hunks))
+
+	for _, hunk := range change.Hunks {
+		if hunk.NewLines == 0 {
+			// lines were only removed, so both neighbours have to be inside the same function
+			function, ok := functionAt(functions, hunk.NewStart)
+			if ok == false || function.lines.End < hunk.NewStart+1 {
+				return nil, false
+			}
+
+			changed[function.name] = struct{}{}
+			continue
+		}
+
+		for line := hunk.NewStart; line < hunk.NewStart+hunk.NewLines; line++ {
+			if function, ok := functionAt(functions, line); ok {
+				changed[function.name] = struct{}{}
+				continue
+			}
+
+			if isBlankLine(lines, line) || inRanges(neutral, line) {
+				continue
+			}
+
+			return nil, false
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil, false // e.g. only the imports changed
+	}
+
+	result := make([]string, 0, len(changed))
+	for name := range changed {
+		result = append(result, name)
+	}
+
+	sort.Strings(result)
+	return result, true
+}
+
+func IsTestFunction(name string) bool {
+	for _, prefix := range testFunctionPrefixes {
+		if strings.HasPrefix(name, prefix) == false {
+			continue
+		}
+
+		if name == "TestMain" {
+			return false
+		}
+
+		rest := strings.TrimPrefix(name, prefix)
+		if rest == "" {
+			return true
+		}
+
+		r, _ := utf8.DecodeRuneInString(rest)
+		return unicode.IsLower(r) == false // like go test, TestXxx but not Testxxx
+	}
+
+	return false
+}
+
+func testFunctions(fileSet *token.FileSet, file *ast.File) []testFunction {
+	result := make([]testFunction, 0, len(file.Decls))
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if ok == false || fn.Recv != nil || IsTestFunction(fn.Name.Name) == false {
+			continue
+		}
+
+		start := fn.Pos()
+		if fn.Doc != nil {
+			start = fn.Doc.Pos()
+		}
+
+		result = append(result, testFunction{
+			name:  fn.Name.Name,
+			lines: LineRange{Start: fileSet.Position(start).Line, End: fileSet.Position(fn.End()).Line},
+		})
+	}
+
+	return result
+}
+
+// neutralLines returns the imports and whole line comments following the package clause, which do not
+// change the behaviour of the tests on their own. The package clause and the comments preceding it
+// are only neutral for new files.
+func neutralLines(fileSet *token.FileSet, file *ast.File, lines []string, added bool) []LineRange {
+	result := make([]LineRange, 0, len(file.Comments)+2)
+	lineOf := func(pos token.Pos) int {
+		return fileSet.Position(pos).Line
+	}
+
+	if added {
+		result = append(result, LineRange{Start: lineOf(file.Package), End: lineOf(file.Name.End())})
+	}
+
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT && hasSideEffects(gen) == false {
+			result = append(result, LineRange{Start: lineOf(gen.Pos()), End: lineOf(gen.End())})
+		}
+	}
+
+	for _, comment := range file.Comments {
+		if comment.Pos() < file.Name.End() && added == false {
+			continue // build constraints and the package documentation
+		}
+
+		position := fileSet.Position(comment.Pos())
+		if strings.TrimSpace(lines[position.Line-1][:position.Column-1]) != "" {
+			continue // trailing a changed statement
+		}
+
+		result = append(result, LineRange{Start: lineOf(comment.Pos()), End: lineOf(comment.End())})
+	}
+
+	return result
+}
+
+func hasSideEffects(imports *ast.GenDecl) bool {
+	for _, spec := range imports.Specs {
+		if importSpec, ok := spec.(*ast.ImportSpec); ok && importSpec.Name != nil && importSpec.Name.Name == "_" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func functionAt(functions []testFunction, line int) (testFunction, bool) {
+	for _, function := range functions {
+		if function.lines.Start <= line && line <= function.lines.End {
+			return function, true
+		}
+	}
+
+	return testFunction{}, false
+}
+
+func inRanges(ranges []LineRange, line int) bool {
+	for _, r := range ranges {
+		if r.Start <= line && line <= r.End {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isBlankLine(lines []string, line int) bool {
+	return line >= 1 && line <= len(lines) && strings.TrimSpace(lines[line-1]) == ""
+}
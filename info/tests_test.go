@@ -0,0 +1,188 @@
+package info_test
+
+import (
+	"github.com/go-lean/bevaluate/info"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const testsSource = `//go:build unit
+
+package baba_test
+
+import (
+	"testing"
+)
+
+func helper() int {
+	return 1
+}
+
+// TestBaba checks baba.
+func TestBaba(t *testing.T) {
+	t.Log("baba")
+	t.Log("is")
+}
+
+func TestYou(t *testing.T) {
+	t.Log(helper()) // uses the helper
+}
+
+func FuzzFlag(f *testing.F) {}
+
+func ExampleWin() {}
+
+func TestMain(m *testing.M) {
+	m.Run()
+}
+`
+
+func TestTestFunctionSelector_ChangedTests(t *testing.T) {
+	testCases := []struct {
+		name       string
+		status     info.ChangeStatus
+		hunks      []info.Hunk
+		expected   []string
+		expectedOk bool
+	}{
+		{
+			name:       "changed test body should select the test",
+			hunks:      []info.Hunk{{OldStart: 15, OldLines: 1, NewStart: 15, NewLines: 1}},
+			expected:   []string{"TestBaba"},
+			expectedOk: true,
+		},
+		{
+			name:       "changed doc comment should select the test",
+			hunks:      []info.Hunk{{OldStart: 13, OldLines: 1, NewStart: 13, NewLines: 1}},
+			expected:   []string{"TestBaba"},
+			expectedOk: true,
+		},
+		{
+			name: "changes to several tests should select all of them",
+			hunks: []info.Hunk{
+				{OldStart: 20, OldLines: 1, NewStart: 20, NewLines: 1},
+				{OldStart: 23, OldLines: 1, NewStart: 23, NewLines: 1},
+				{OldStart: 25, OldLines: 1, NewStart: 25, NewLines: 1},
+			},
+			expected:   []string{"ExampleWin", "FuzzFlag", "TestYou"},
+			expectedOk: true,
+		},
+		{
+			name:       "removed lines inside a test should select the test",
+			hunks:      []info.Hunk{{OldStart: 16, OldLines: 2, NewStart: 15, NewLines: 0}},
+			expected:   []string{"TestBaba"},
+			expectedOk: true,
+		},
+		{
+			name:  "removed lines between functions should fall back",
+			hunks: []info.Hunk{{OldStart: 18, OldLines: 4, NewStart: 17, NewLines: 0}},
+		},
+		{
+			name: "changed import with a test should select the test",
+			hunks: []info.Hunk{
+				{OldStart: 6, OldLines: 1, NewStart: 6, NewLines: 1},
+				{OldStart: 15, OldLines: 1, NewStart: 15, NewLines: 1},
+			},
+			expected:   []string{"TestBaba"},
+			expectedOk: true,
+		},
+		{
+			name:  "only changed imports should fall back",
+			hunks: []info.Hunk{{OldStart: 6, OldLines: 1, NewStart: 6, NewLines: 1}},
+		},
+		{
+			name:  "changed helper should fall back",
+			hunks: []info.Hunk{{OldStart: 10, OldLines: 1, NewStart: 10, NewLines: 1}},
+		},
+		{
+			name:  "changed TestMain should fall back",
+			hunks: []info.Hunk{{OldStart: 28, OldLines: 1, NewStart: 28, NewLines: 1}},
+		},
+		{
+			name:  "changed build constraint should fall back",
+			hunks: []info.Hunk{{OldStart: 1, OldLines: 1, NewStart: 1, NewLines: 1}},
+		},
+		{
+			name:   "added file with a helper should fall back",
+			status: info.StatusAdded,
+			hunks:  []info.Hunk{{OldStart: 0, OldLines: 0, NewStart: 1, NewLines: 29}},
+		},
+		{
+			name:  "unknown hunks should fall back",
+			hunks: nil,
+		},
+		{
+			name:   "deleted file should fall back",
+			status: info.StatusDeleted,
+			hunks:  []info.Hunk{{OldStart: 1, OldLines: 29, NewStart: 0, NewLines: 0}},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			status := c.status
+			if status == 0 {
+				status = info.StatusModified
+			}
+
+			versions := NewFakeVersions().MockAt("baba/baba_test.go", testsSource, testsSource)
+			selector := info.NewTestFunctionSelector(versions)
+
+			tests, ok := selector.ChangedTests(info.ChangeInfo{Status: status, Path: "baba/baba_test.go", Hunks: c.hunks})
+
+			require.Equal(t, c.expectedOk, ok)
+			if c.expectedOk {
+				require.Equal(t, c.expected, tests)
+			}
+		})
+	}
+}
+
+func TestTestFunctionSelector_ChangedTests_AddedTestsOnly(t *testing.T) {
+	source := `// Copyright baba.
+
+package baba_test
+
+import "testing"
+
+func TestBaba(t *testing.T) {}
+
+func TestIs(t *testing.T) {}
+`
+	versions := NewFakeVersions().MockAt("baba/is_test.go", "", source)
+	selector := info.NewTestFunctionSelector(versions)
+	change := info.ChangeInfo{
+		Status: info.StatusAdded,
+		Path:   "baba/is_test.go",
+		Hunks:  []info.Hunk{{NewStart: 1, NewLines: 9}},
+	}
+
+	tests, ok := selector.ChangedTests(change)
+
+	require.True(t, ok)
+	require.Equal(t, []string{"TestBaba", "TestIs"}, tests)
+}
+
+func TestTestFunctionSelector_ChangedTests_NotTestFile_Fallback(t *testing.T) {
+	selector := info.NewTestFunctionSelector(NewFakeVersions().MockAt("baba/baba.go", "", testsSource))
+
+	_, ok := selector.ChangedTests(info.ChangeInfo{
+		Status: info.StatusModified,
+		Path:   "baba/baba.go",
+		Hunks:  []info.Hunk{{NewStart: 16, NewLines: 1}},
+	})
+
+	require.False(t, ok)
+}
+
+func TestIsTestFunction(t *testing.T) {
+	require.True(t, info.IsTestFunction("TestBaba"))
+	require.True(t, info.IsTestFunction("Test"))
+	require.True(t, info.IsTestFunction("Test_baba"))
+	require.True(t, info.IsTestFunction("FuzzBaba"))
+	require.True(t, info.IsTestFunction("Example"))
+	require.True(t, info.IsTestFunction("ExampleBaba_Is"))
+	require.False(t, info.IsTestFunction("Testbaba"))
+	require.False(t, info.IsTestFunction("TestMain"))
+	require.False(t, info.IsTestFunction("BenchmarkBaba"))
+}
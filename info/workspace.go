@@ -0,0 +1,215 @@
+package info
+
+import (
+	"fmt"
+	"github.com/go-lean/bevaluate/models"
+	"github.com/go-lean/bevaluate/storage"
+	"github.com/zyedidia/generic/stack"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type (
+	ModuleReader struct {
+		fileOpener FileOpener
+		dirReader  DirReader
+		config     Config
+	}
+)
+
+const (
+	goWorkFile    = "go.work"
+	goWorkSumFile = "go.work.sum"
+	rootDir       = "."
+)
+
+func NewModuleReader(dirReader DirReader, fileOpener FileOpener, cfg Config) ModuleReader {
+	return ModuleReader{
+		dirReader:  dirReader,
+		fileOpener: fileOpener,
+		config:     cfg,
+	}
+}
+
+func (r ModuleReader) ReadModules(root string) ([]Module, error) {
+	entries, errRead := r.dirReader.Read(root)
+	if errRead != nil {
+		return nil, fmt.Errorf("could not read root directory: %w", errRead)
+	}
+
+	var dirs []string
+	workspace := containsFile(entries, goWorkFile)
+	if workspace {
+		workDirs, errWork := storage.ReadWorkspaceDirs(filepath.Join(root, goWorkFile), r.fileOpener)
+		if errWork != nil {
+			return nil, fmt.Errorf("could not read workspace file: %w", errWork)
+		}
+
+		dirs = r.workspaceModuleDirs(workDirs)
+	} else {
+		moduleDirs, errFind := r.findModuleDirs(root, entries)
+		if errFind != nil {
+			return nil, fmt.Errorf("could not find modules: %w", errFind)
+		}
+
+		dirs = moduleDirs
+	}
+
+	if len(dirs) == 0 {
+		return nil, fmt.Errorf("could not find any go module in: %q", root)
+	}
+
+	sort.Strings(dirs)
+	modules := make([]Module, 0, len(dirs))
+
+	for _, dir := range dirs {
+		name, errName := storage.ReadModuleName(filepath.Join(root, dir, goModFile), r.fileOpener)
+		if errName != nil {
+			return nil, fmt.Errorf("could not read go module name: %w", errName)
+		}
+
+		modules = append(modules, Module{Path: name, Dir: dir, Workspace: workspace})
+	}
+
+	return modules, nil
+}
+
+func (r ModuleReader) workspaceModuleDirs(workDirs []string) []string {
+	dirs := make([]string, 0, len(workDirs))
+
+	for _, workDir := range workDirs {
+		dir := filepath.Clean(filepath.FromSlash(workDir))
+		if filepath.IsAbs(dir) || dir == ".." || strings.HasPrefix(dir, ".."+string(filepath.Separator)) {
+			continue // modules outside the repository can not contain changes
+		}
+
+		if dir != rootDir && r.config.IsIgnored(dir) {
+			continue
+		}
+
+		dirs = append(dirs, dir)
+	}
+
+	return dirs
+}
+
+func (r ModuleReader) findModuleDirs(root string, rootEntries []models.DirEntry) ([]string, error) {
+	dirs := make([]string, 0)
+	if containsFile(rootEntries, goModFile) {
+		dirs = append(dirs, rootDir)
+	}
+
+	dirsStack := stack.New[string]()
+	pushSubDirs(dirsStack, rootDir, rootEntries, r.config)
+
+	for dirsStack.Size() > 0 {
+		dir := dirsStack.Pop()
+
+		entries, errRead := r.dirReader.Read(filepath.Join(root, dir))
+		if errRead != nil {
+			return nil, fmt.Errorf("could not read dir: %w", errRead)
+		}
+
+		if containsFile(entries, goModFile) {
+			dirs = append(dirs, dir)
+		}
+
+		pushSubDirs(dirsStack, dir, entries, r.config)
+	}
+
+	return dirs, nil
+}
+
+// IsWorkspaceFile reports whether the path is the root go.work or go.work.sum, which select the
+// modules and the versions every one of them is built with.
+func IsWorkspaceFile(path string) bool {
+	return path == goWorkFile || path == goWorkSumFile
+}
+
+func (m Module) ImportPath(dir string) string {
+	rel, _ := filepath.Rel(m.Dir, dir)
+	return path.Join(m.Path, filepath.ToSlash(rel))
+}
+
+func ModuleOf(dir string, modules []Module) (Module, bool) {
+	result := Module{}
+	depth := -1
+
+	for _, module := range modules {
+		if isInDir(dir, module.Dir) == false {
+			continue
+		}
+
+		moduleDepth := len(module.Dir)
+		if module.Dir == rootDir {
+			moduleDepth = 0
+		}
+
+		if moduleDepth > depth {
+			result = module
+			depth = moduleDepth
+		}
+	}
+
+	return result, depth >= 0
+}
+
+func resolveImport(importPath string, modules []Module) (string, bool) {
+	owner := Module{}
+	found := false
+
+	for _, module := range modules {
+		if isModuleImport(importPath, module.Path) && len(module.Path) > len(owner.Path) {
+			owner = module
+			found = true
+		}
+	}
+
+	if found == false {
+		return "", false
+	}
+
+	rel := strings.TrimPrefix(strings.TrimPrefix(importPath, owner.Path), "/")
+	return filepath.Join(owner.Dir, filepath.FromSlash(rel)), true
+}
+
+func isModuleDir(dir string, modules []Module) bool {
+	for _, module := range modules {
+		if module.Dir == dir {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isInDir(path, dir string) bool {
+	return dir == rootDir || path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+func containsFile(entries []models.DirEntry, name string) bool {
+	for _, entry := range entries {
+		if entry.IsDir() == false && entry.Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func pushSubDirs(dirsStack *stack.Stack[string], dir string, entries []models.DirEntry, cfg Config) {
+	for _, entry := range entries {
+		if entry.IsDir() == false || isSkippedByGo(entry.Name()) {
+			continue // like testdata, go never treats those as part of the build
+		}
+
+		entryPath := filepath.Join(dir, entry.Name())
+		if cfg.IsIgnored(entryPath) {
+			continue
+		}
+
+		dirsStack.Push(entryPath)
+	}
+}
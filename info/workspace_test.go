@@ -0,0 +1,137 @@
+package info_test
+
+import (
+	"github.com/go-lean/bevaluate/info"
+	"github.com/go-lean/bevaluate/models"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestModuleReader_ReadModules_DirReaderError(t *testing.T) {
+	r := info.NewModuleReader(NewDirReader().CanRead(false), NewFileOpener(), emptyConfig)
+
+	modules, errRead := r.ReadModules("baba")
+
+	require.Error(t, errRead)
+	require.Contains(t, errRead.Error(), "kaboom")
+	require.Empty(t, modules)
+}
+
+func TestModuleReader_ReadModules_NoModules_Error(t *testing.T) {
+	dirReader := NewDirReader()
+	dirReader.MockAt("baba", []models.DirEntry{DirEntry{name: "README.md"}})
+
+	r := info.NewModuleReader(dirReader, NewFileOpener(), emptyConfig)
+
+	modules, errRead := r.ReadModules("baba")
+
+	require.Error(t, errRead)
+	require.Empty(t, modules)
+}
+
+func TestModuleReader_ReadModules_NestedModules(t *testing.T) {
+	dirReader := NewDirReader()
+	dirReader.MockAt("baba", []models.DirEntry{
+		DirEntry{name: "go.mod"},
+		DirEntry{name: "common", isDir: true},
+		DirEntry{name: "services", isDir: true},
+		DirEntry{name: "ignored", isDir: true},
+		DirEntry{name: "testdata", isDir: true},
+		DirEntry{name: ".hidden", isDir: true},
+		DirEntry{name: "_old", isDir: true},
+	})
+	for _, skipped := range []string{"baba/testdata", "baba/.hidden", "baba/_old"} {
+		dirReader.MockAt(skipped, []models.DirEntry{DirEntry{name: "go.mod"}})
+	}
+	dirReader.MockAt("baba/common", []models.DirEntry{DirEntry{name: "common.go"}})
+	dirReader.MockAt("baba/services", []models.DirEntry{DirEntry{name: "api", isDir: true}})
+	dirReader.MockAt("baba/services/api", []models.DirEntry{
+		DirEntry{name: "go.mod"},
+		DirEntry{name: "main.go"},
+	})
+
+	opener := NewFileOpener()
+	opener.MockAt("baba/go.mod", NewFakeFile("module github.com/baba/is/you\n"))
+	opener.MockAt("baba/services/api/go.mod", NewFakeFile("module github.com/baba/is/api\n"))
+
+	r := info.NewModuleReader(dirReader, opener, info.NewConfig("ignored"))
+
+	modules, errRead := r.ReadModules("baba")
+
+	require.NoError(t, errRead)
+	require.Equal(t, []info.Module{
+		{Path: "github.com/baba/is/you", Dir: "."},
+		{Path: "github.com/baba/is/api", Dir: "services/api"},
+	}, modules)
+}
+
+func TestModuleReader_ReadModules_Workspace(t *testing.T) {
+	dirReader := NewDirReader()
+	dirReader.MockAt("baba", []models.DirEntry{
+		DirEntry{name: "go.work"},
+		DirEntry{name: "tools", isDir: true},
+		DirEntry{name: "api", isDir: true},
+	})
+
+	opener := NewFileOpener()
+	opener.MockAt("baba/go.work", NewFakeFile(`go 1.21
+
+use (
+	./api
+	./tools // tooling
+	../outside
+)
+`))
+	opener.MockAt("baba/api/go.mod", NewFakeFile("module github.com/baba/is/api\n"))
+	opener.MockAt("baba/tools/go.mod", NewFakeFile("module github.com/baba/is/tools\n"))
+
+	r := info.NewModuleReader(dirReader, opener, emptyConfig)
+
+	modules, errRead := r.ReadModules("baba")
+
+	require.NoError(t, errRead)
+	require.Equal(t, []info.Module{
+		{Path: "github.com/baba/is/api", Dir: "api", Workspace: true},
+		{Path: "github.com/baba/is/tools", Dir: "tools", Workspace: true},
+	}, modules)
+}
+
+func TestModuleReader_ReadModules_ModuleNameError(t *testing.T) {
+	dirReader := NewDirReader()
+	dirReader.MockAt("baba", []models.DirEntry{DirEntry{name: "go.mod"}})
+
+	r := info.NewModuleReader(dirReader, NewFileOpener().CanOpen(false), emptyConfig)
+
+	modules, errRead := r.ReadModules("baba")
+
+	require.Error(t, errRead)
+	require.Contains(t, errRead.Error(), "kaboom")
+	require.Empty(t, modules)
+}
+
+func TestModuleOf(t *testing.T) {
+	modules := []info.Module{
+		{Path: "github.com/baba/is/you", Dir: "."},
+		{Path: "github.com/baba/is/api", Dir: "services/api"},
+	}
+
+	module, ok := info.ModuleOf("services/api/handler", modules)
+	require.True(t, ok)
+	require.Equal(t, "services/api", module.Dir)
+	require.Equal(t, "github.com/baba/is/api/handler", module.ImportPath("services/api/handler"))
+
+	module, ok = info.ModuleOf("services/apis", modules)
+	require.True(t, ok)
+	require.Equal(t, ".", module.Dir)
+	require.Equal(t, "github.com/baba/is/you/services/apis", module.ImportPath("services/apis"))
+
+	_, ok = info.ModuleOf("baba", modules[1:])
+	require.False(t, ok)
+}
+
+func TestIsWorkspaceFile(t *testing.T) {
+	require.True(t, info.IsWorkspaceFile("go.work"))
+	require.True(t, info.IsWorkspaceFile("go.work.sum"))
+	require.False(t, info.IsWorkspaceFile("services/go.work"))
+	require.False(t, info.IsWorkspaceFile("go.mod"))
+}
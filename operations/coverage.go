@@ -0,0 +1,115 @@
+package operations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-lean/bevaluate/config"
+	"github.com/go-lean/bevaluate/evaluate"
+	"github.com/go-lean/bevaluate/info"
+	"github.com/go-lean/bevaluate/storage"
+	"io/fs"
+	"path/filepath"
+)
+
+type (
+	CoverageOperation struct {
+		cfg   config.Config
+		store storage.Store
+	}
+)
+
+var (
+	ErrNoCoverageIndex = errors.New("no coverage index configured")
+)
+
+func NewCoverageOperation(store storage.Store, cfg config.Config) CoverageOperation {
+	return CoverageOperation{
+		cfg:   cfg,
+		store: store,
+	}
+}
+
+func (o CoverageOperation) Run(root, testPkg, profile string) error {
+	path := o.cfg.Evaluations.CoverageIndex
+	if path == "" {
+		return ErrNoCoverageIndex
+	}
+
+	infoCfg := info.NewConfig(o.cfg.Packages.IgnoredDirs...)
+	modules, errModules := info.NewModuleReader(o.store.DirReader, o.store.FileOpener, infoCfg).ReadModules(root)
+	if errModules != nil {
+		return fmt.Errorf("could not read go modules: %w", errModules)
+	}
+
+	coverage, errParse := info.ParseCoverProfile(profile, modules)
+	if errParse != nil {
+		return fmt.Errorf("could not parse cover profile: %w", errParse)
+	}
+
+	if coversOnlyPackage(coverage, testPkg) {
+		warn("the cover profile only covers its own package, record it with -coverpkg to select the tests of dependants")
+	}
+
+	index, _, errRead := readCoverageIndex(path, o.store.FileOpener)
+	if errRead != nil {
+		return errRead
+	}
+
+	data, errMarshal := json.Marshal(index.Record(testPkg, coverage))
+	if errMarshal != nil {
+		return fmt.Errorf("could not marshal coverage index: %w", errMarshal)
+	}
+
+	if errWrite := storage.CreateFileWithText(path, string(data), o.store.FileOpener); errWrite != nil {
+		return fmt.Errorf("could not write coverage index: %w", errWrite)
+	}
+
+	return nil
+}
+
+func coversOnlyPackage(coverage map[string][]info.LineRange, testPkg string) bool {
+	for path := range coverage {
+		if filepath.Dir(path) != testPkg {
+			return false
+		}
+	}
+
+	return len(coverage) > 0
+}
+
+func (o EvaluateBuildOperation) withCoverage(evaluator evaluate.BuildEvaluator) (evaluate.BuildEvaluator, error) {
+	if o.cfg.Evaluations.CoverageIndex == "" {
+		return evaluator, nil
+	}
+
+	index, found, errRead := readCoverageIndex(o.cfg.Evaluations.CoverageIndex, o.store.FileOpener)
+	if errRead != nil {
+		return evaluator, errRead
+	}
+
+	if found == false {
+		warn("no coverage was recorded yet, evaluating without coverage")
+		return evaluator, nil
+	}
+
+	return evaluator.WithCoverage(index), nil
+}
+
+func readCoverageIndex(path string, opener storage.FileReadOpener) (info.CoverageIndex, bool, error) {
+	text, errRead := storage.ReadText(path, opener)
+	if errors.Is(errRead, fs.ErrNotExist) {
+		return info.NewCoverageIndex(), false, nil
+	}
+
+	if errRead != nil {
+		return info.CoverageIndex{}, false, fmt.Errorf("could not read coverage index: %w", errRead)
+	}
+
+	index := info.NewCoverageIndex()
+	if errUnmarshal := json.Unmarshal([]byte(text), &index); errUnmarshal != nil {
+		return info.CoverageIndex{}, false, fmt.Errorf("could not unmarshal coverage index: %w", errUnmarshal)
+	}
+
+	return index, true, nil
+}
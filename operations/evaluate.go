@@ -1,19 +1,36 @@
 package operations
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/go-lean/bevaluate/config"
 	"github.com/go-lean/bevaluate/evaluate"
+	"github.com/go-lean/bevaluate/gotest"
 	"github.com/go-lean/bevaluate/info"
 	"github.com/go-lean/bevaluate/storage"
+	"io"
+	"os"
 	"path/filepath"
-	"strings"
+	"time"
 )
 
 type (
 	EvaluateBuildOperation struct {
-		cfg   config.Config
-		store storage.Store
+		cfg            config.Config
+		store          storage.Store
+		versions       info.SourceVersions
+		explanation    *explanation
+		gitHubOutput   io.Writer
+		gitLabPipeline bool
+		tests          *tests
+		shard          *gotest.Shard
+		skipOutputs    bool
+	}
+
+	explanation struct {
+		out      io.Writer
+		asJSON   bool
+		packages []string
 	}
 )
 
@@ -24,61 +41,449 @@ func NewEvaluateOperation(store storage.Store, cfg config.Config) EvaluateBuildO
 	}
 }
 
-func (o EvaluateBuildOperation) Run(root, changesContent string) error {
-	changes, errParse := info.ParseGitChanges(changesContent)
-	if errParse != nil {
-		return fmt.Errorf("could not parse changes: %w", errParse)
+func (o EvaluateBuildOperation) WithVersions(versions info.SourceVersions) EvaluateBuildOperation {
+	o.versions = versions
+	return o
+}
+
+func (o EvaluateBuildOperation) WithExplanation(out io.Writer, asJSON bool, packages ...string) EvaluateBuildOperation {
+	o.explanation = &explanation{
+		out:      out,
+		asJSON:   asJSON,
+		packages: packages,
 	}
+	return o
+}
 
+func (o EvaluateBuildOperation) WithGitHubOutput(out io.Writer) EvaluateBuildOperation {
+	o.gitHubOutput = out
+	return o
+}
+
+func (o EvaluateBuildOperation) WithoutOutputs() EvaluateBuildOperation {
+	o.skipOutputs = true
+	return o
+}
+
+func (o EvaluateBuildOperation) Run(root string, changes []info.ChangeInfo) error {
+	started := time.Now()
 	if len(changes) == 0 {
-		return nil
+		return o.finishEmpty(evaluationRun{started: started})
 	}
 
-	moduleName, errName := storage.ReadModuleName(filepath.Join(root, "go.mod"), o.store.FileOpener)
-	if errName != nil {
-		return fmt.Errorf("could not read go module name: %w", errName)
+	if errStyle := validatePathStyle(o.cfg.Evaluations.PathStyle); errStyle != nil {
+		return errStyle
 	}
 
 	infoCfg := info.NewConfig(o.cfg.Packages.IgnoredDirs...)
+	moduleReader := info.NewModuleReader(o.store.DirReader, o.store.FileOpener, infoCfg)
+
+	modules, errModules := moduleReader.ReadModules(root)
+	if errModules != nil {
+		return fmt.Errorf("could not read go modules: %w", errModules)
+	}
+
 	packageReader := info.NewPackageReader(o.store.DirReader, o.store.FileOpener, infoCfg)
+	evaluator, errCoverage := o.withCoverage(o.newEvaluator(root, modules))
+	if errCoverage != nil {
+		return errCoverage
+	}
 
-	packages, errRead := packageReader.ReadRecursively(root, moduleName)
-	if errRead != nil {
-		return fmt.Errorf("could not read packages: %w", errRead)
+	run := evaluationRun{
+		root:    root,
+		started: started,
+		modules: modules,
+		changes: changes,
+		sizes:   make(map[string]int),
 	}
 
-	if len(packages) == 0 {
+	if o.cfg.Evaluations.PathStyle == PathStylePattern && o.skipOutputs == false {
+		ignored, errIgnored := packageReader.ReadIgnoredDirs(root)
+		if errIgnored != nil {
+			return fmt.Errorf("could not read ignored dirs: %w", errIgnored)
+		}
+
+		run.ignored = qualifyDirs(ignored, modules)
+	}
+
+	if o.shard != nil {
+		history, errHistory := o.readHistory()
+		if errHistory != nil {
+			return errHistory
+		}
+
+		run.history = history
+	}
+
+	if len(o.cfg.Packages.Targets) == 0 {
+		result, packages, errEvaluate := evaluateBuild(evaluator, packageReader, root, modules, changes)
+		if errEvaluate != nil {
+			return errEvaluate
+		}
+
+		if len(packages) == 0 {
+			return o.finishEmpty(run)
+		}
+
+		recordSizes(run.sizes, packages)
+
+		return o.finish(result, run, nil)
+	}
+
+	merged := evaluate.Evaluation{}
+	targets := make([]string, 0, len(o.cfg.Packages.Targets))
+
+	for _, cfgTarget := range o.cfg.Packages.Targets {
+		target := newTarget(cfgTarget)
+
+		result, packages, errEvaluate := evaluateBuild(evaluator, packageReader.WithTarget(target), root, modules, changes)
+		if errEvaluate != nil {
+			return fmt.Errorf("could not evaluate target %q: %w", target.Name, errEvaluate)
+		}
+
+		if len(packages) == 0 {
+			continue
+		}
+
+		recordSizes(run.sizes, packages)
+		if errWrite := o.writeResult(o.selectShard(result, run), run, target.Name); errWrite != nil {
+			return fmt.Errorf("could not write result of target %q: %w", target.Name, errWrite)
+		}
+
+		merged = merged.Merge(result)
+		targets = append(targets, target.Name)
+	}
+
+	if len(targets) == 0 {
+		return o.finishEmpty(run)
+	}
+
+	return o.finish(merged, run, targets)
+}
+
+func (o EvaluateBuildOperation) finish(result evaluate.Evaluation, run evaluationRun, targets []string) error {
+	result = o.selectShard(result, run)
+
+	if errWrite := o.writeResult(result, run, ""); errWrite != nil {
+		return fmt.Errorf("could not write result: %w", errWrite)
+	}
+
+	if errReport := o.writeReport(result, run, targets); errReport != nil {
+		return errReport
+	}
+
+	if errFormats := o.writeFormats(result); errFormats != nil {
+		return errFormats
+	}
+
+	if errExplain := o.explain(result, run); errExplain != nil {
+		return fmt.Errorf("could not explain result: %w", errExplain)
+	}
+
+	return o.runTests(result, run)
+}
+
+// finishEmpty writes the report and the formats of a run which selected nothing, so consumers always
+// find an up to date report instead of the one of a previous run.
+func (o EvaluateBuildOperation) finishEmpty(run evaluationRun) error {
+	if errReport := o.writeReport(evaluate.Evaluation{}, run, nil); errReport != nil {
+		return errReport
+	}
+
+	if errFormats := o.writeFormats(evaluate.Evaluation{}); errFormats != nil {
+		return errFormats
+	}
+
+	if errExplain := o.explain(evaluate.Evaluation{}, run); errExplain != nil {
+		return fmt.Errorf("could not explain result: %w", errExplain)
+	}
+
+	return nil
+}
+
+func (o EvaluateBuildOperation) writeReport(result evaluate.Evaluation, run evaluationRun, targets []string) error {
+	if o.skipOutputs || o.cfg.Evaluations.ReportOut == "" {
+		return nil
+	}
+
+	report := newReport(run, result, targets)
+	if errWrite := writeReport(o.cfg.Evaluations.ReportOut, report, o.store.FileOpener); errWrite != nil {
+		return fmt.Errorf("could not write report: %w", errWrite)
+	}
+
+	return nil
+}
+
+func (o EvaluateBuildOperation) writeFormats(result evaluate.Evaluation) error {
+	if o.gitHubOutput != nil {
+		if errWrite := writeGitHubOutputs(o.gitHubOutput, result); errWrite != nil {
+			return fmt.Errorf("could not write github outputs: %w", errWrite)
+		}
+	}
+
+	if o.gitLabPipeline {
+		if errWrite := o.writeGitLabPipeline(result); errWrite != nil {
+			return fmt.Errorf("could not write gitlab pipeline: %w", errWrite)
+		}
+	}
+
+	return nil
+}
+
+func (o EvaluateBuildOperation) explain(result evaluate.Evaluation, run evaluationRun) error {
+	if o.explanation == nil {
 		return nil
 	}
 
+	packages := o.explanation.packages
+	if len(packages) == 0 {
+		packages = mergeSelected(result)
+	}
+
+	explanations := make([]evaluate.Explanation, 0, len(packages))
+	for _, pkg := range packages {
+		explanations = append(explanations, result.Explain(pkg))
+	}
+
+	if o.explanation.asJSON {
+		encoder := json.NewEncoder(o.explanation.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(explanations)
+	}
+
+	if len(explanations) == 0 {
+		return explainNothingSelected(o.explanation.out, run)
+	}
+
+	for _, e := range explanations {
+		if _, errWrite := fmt.Fprintln(o.explanation.out, e.String()); errWrite != nil {
+			return errWrite
+		}
+	}
+
+	return nil
+}
+
+// explainNothingSelected tells apart a run without any change from one whose changes selected nothing,
+// since both of them would otherwise print nothing at all.
+func explainNothingSelected(out io.Writer, run evaluationRun) error {
+	message := "no package is affected by the changes"
+	if len(run.changes) == 0 {
+		message = "no changes"
+	}
+
+	_, errWrite := fmt.Fprintln(out, message)
+	return errWrite
+}
+
+func (o EvaluateBuildOperation) newEvaluator(root string, modules []info.Module) evaluate.BuildEvaluator {
 	evalCfg := evaluate.NewConfig(
 		o.cfg.Evaluations.DeploymentsDir,
 		o.cfg.Evaluations.SpecialCases.RetestTriggers,
 		o.cfg.Evaluations.SpecialCases.FullScaleTriggers)
 
+	ownership := make([]evaluate.OwnershipRule, 0, len(o.cfg.Evaluations.Ownership))
+	for _, rule := range o.cfg.Evaluations.Ownership {
+		ownership = append(ownership, evaluate.NewOwnershipRule(rule.Match, rule.Owners...))
+	}
+
+	scopedTriggers := make([]evaluate.ScopedTrigger, 0, len(o.cfg.Evaluations.SpecialCases.ScopedTriggers))
+	for _, trigger := range o.cfg.Evaluations.SpecialCases.ScopedTriggers {
+		scopedTriggers = append(scopedTriggers,
+			evaluate.NewScopedTrigger(trigger.Match, trigger.Retest, trigger.Redeploy, trigger.Propagate))
+	}
+
+	deployables := o.cfg.Evaluations.Deployables
+	evalCfg = evalCfg.
+		WithOwnership(ownership...).
+		WithScopedTriggers(scopedTriggers...).
+		WithDeployables(evaluate.NewDeployableRules(
+			deployables.MainPackages,
+			deployables.Markers,
+			deployables.Patterns,
+			deployables.Include,
+			deployables.Exclude))
+
 	evaluator := evaluate.NewBuildEvaluator(evalCfg)
+	if o.cfg.Evaluations.SkipNeutralChanges {
+		if o.versions == nil {
+			warn("skipping neutral changes requires the changes to be computed from git, evaluating all changes")
+		} else {
+			classifier := info.NewNeutralChangeClassifier(o.versions, o.store.DirReader, root)
+			evaluator = evaluator.WithClassifier(classifier)
+		}
+	}
+
+	if o.cfg.Evaluations.SymbolAnalysis {
+		if o.versions == nil {
+			warn("symbol analysis requires the changes to be computed from git, evaluating whole packages")
+		} else {
+			analyzer := info.NewSymbolAnalyzer(o.versions, o.store.DirReader, root, modules)
+			evaluator = evaluator.WithAnalyzer(analyzer)
+		}
+	}
+
+	if o.cfg.Evaluations.ModuleDiff {
+		if o.versions == nil {
+			warn("module diff requires the changes to be computed from git, evaluating module files as special cases")
+		} else {
+			evaluator = evaluator.WithModuleDiffer(info.NewModuleDiffer(o.versions).WithGraph(gotest.CommandRunner{}, root))
+		}
+	}
+
+	if o.cfg.Evaluations.SelectTestFunctions {
+		if o.versions == nil {
+			warn("selecting test functions requires the changes to be computed from git, retesting whole packages")
+		} else {
+			evaluator = evaluator.WithTestSelector(info.NewTestFunctionSelector(o.versions))
+		}
+	}
+
+	return evaluator
+}
+
+func evaluateBuild(evaluator evaluate.BuildEvaluator, reader info.PackageReader, root string, modules []info.Module, changes []info.ChangeInfo) (evaluate.Evaluation, []info.PackageInfo, error) {
+	packages, errRead := reader.ReadModules(root, modules)
+	if errRead != nil {
+		return evaluate.Evaluation{}, nil, fmt.Errorf("could not read packages: %w", errRead)
+	}
+
+	if len(packages) == 0 {
+		return evaluate.Evaluation{}, nil, nil
+	}
+
 	result, errEvaluate := evaluator.Evaluate(packages, changes)
 	if errEvaluate != nil {
-		return fmt.Errorf("could not evaluate build: %w", errEvaluate)
+		return evaluate.Evaluation{}, nil, fmt.Errorf("could not evaluate build: %w", errEvaluate)
 	}
 
-	if errWrite := o.writeResult(result); errWrite != nil {
-		return fmt.Errorf("could not write result: %w", errWrite)
+	if len(modules) > 1 {
+		result = qualifyByModule(result, packages)
 	}
 
-	return nil
+	return result, packages, nil
 }
 
-func (o EvaluateBuildOperation) writeResult(result evaluate.Evaluation) error {
-	retestContent := strings.Join(result.Retest, storage.NewLine)
-	if errWrite := storage.CreateFileWithText(o.cfg.Evaluations.RetestOut, retestContent, o.store.FileOpener); errWrite != nil {
-		return fmt.Errorf("could not write retest result: %w", errWrite)
+func (o EvaluateBuildOperation) writeResult(result evaluate.Evaluation, run evaluationRun, target string) error {
+	if o.skipOutputs {
+		return nil
 	}
 
-	redeployContent := strings.Join(result.Redeploy, storage.NewLine)
-	if errWrite := storage.CreateFileWithText(o.cfg.Evaluations.RedeployOut, redeployContent, o.store.FileOpener); errWrite != nil {
-		return fmt.Errorf("could not write redeploy result: %w", errWrite)
+	data := newOutputData(result, run.modules, run.ignored, target, o.cfg.Evaluations.PathStyle)
+	for _, output := range o.outputs() {
+		content, errRender := renderOutput(output, data)
+		if errRender != nil {
+			return fmt.Errorf("could not render %q: %w", output.Path, errRender)
+		}
+
+		if errWrite := storage.CreateFileWithText(targetOut(output.Path, target), content, o.store.FileOpener); errWrite != nil {
+			return fmt.Errorf("could not write %q: %w", output.Path, errWrite)
+		}
 	}
 
 	return nil
 }
+
+// qualifyDirs qualifies the dirs like the packages of several modules, dropping the ones outside of them.
+func qualifyDirs(dirs []string, modules []info.Module) []string {
+	if len(modules) < 2 {
+		return dirs
+	}
+
+	result := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		if module, ok := info.ModuleOf(dir, modules); ok {
+			result = append(result, info.PackageInfo{Path: dir, Module: module}.QualifiedPath())
+		}
+	}
+
+	return result
+}
+
+func qualifyByModule(result evaluate.Evaluation, packages []info.PackageInfo) evaluate.Evaluation {
+	qualified := make(map[string]string, len(packages))
+	for _, pkg := range packages {
+		qualified[pkg.Path] = pkg.QualifiedPath()
+	}
+
+	for i, path := range result.Retest {
+		result.Retest[i] = qualified[path]
+	}
+
+	for i, path := range result.Redeploy {
+		result.Redeploy[i] = qualified[path]
+	}
+
+	for _, paths := range [][]string{result.Testable, result.Deployable, result.Packages, result.Excluded} {
+		for i, path := range paths {
+			paths[i] = qualified[path]
+		}
+	}
+
+	reasons := make(map[string]evaluate.Reason, len(result.Reasons))
+	for path, reason := range result.Reasons {
+		reasons[qualified[path]] = reason
+	}
+
+	result.Reasons = reasons
+
+	testFunctions := make(map[string][]string, len(result.TestFunctions))
+	for path, functions := range result.TestFunctions {
+		testFunctions[qualified[path]] = functions
+	}
+
+	result.TestFunctions = testFunctions
+
+	deployments := make(map[string]info.DeploymentManifest, len(result.Deployments))
+	for path, manifest := range result.Deployments {
+		deployments[qualified[path]] = manifest
+	}
+
+	result.Deployments = deployments
+
+	return result
+}
+
+func newTarget(cfg config.Target) info.Target {
+	name := cfg.Name
+	if name == "" {
+		name = cfg.GOOS + "_" + cfg.GOARCH
+	}
+
+	return info.Target{
+		Name:   name,
+		GOOS:   cfg.GOOS,
+		GOARCH: cfg.GOARCH,
+		Tags:   cfg.Tags,
+		Cgo:    cfg.Cgo,
+	}
+}
+
+func targetOut(path, target string) string {
+	if target == "" {
+		return path
+	}
+
+	return filepath.Join(filepath.Dir(path), target, filepath.Base(path))
+}
+
+func mergeSelected(result evaluate.Evaluation) []string {
+	seen := make(map[string]struct{}, len(result.Retest))
+	selected := make([]string, 0, len(result.Retest)+len(result.Redeploy))
+
+	for _, pkg := range append(result.Retest, result.Redeploy...) {
+		if _, ok := seen[pkg]; ok {
+			continue
+		}
+
+		seen[pkg] = struct{}{}
+		selected = append(selected, pkg)
+	}
+
+	return selected
+}
+
+// warn reports a degraded evaluation on stderr, keeping stdout parseable for the json and github outputs.
+func warn(message string) {
+	_, _ = fmt.Fprintln(os.Stderr, message)
+}
@@ -0,0 +1,46 @@
+package operations
+
+import (
+	"github.com/go-lean/bevaluate/config"
+	"github.com/go-lean/bevaluate/evaluate"
+	"github.com/go-lean/bevaluate/info"
+	"github.com/go-lean/bevaluate/storage"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestEvaluateBuildOperation_Run_NoChanges_ExplainsNoChanges(t *testing.T) {
+	out := strings.Builder{}
+	operation := NewEvaluateOperation(storage.Store{}, config.Default()).
+		WithoutOutputs().
+		WithExplanation(&out, false)
+
+	err := operation.Run("root", nil)
+
+	require.NoError(t, err)
+	require.Equal(t, "no changes\n", out.String())
+}
+
+func TestEvaluateBuildOperation_Run_NoChanges_ExplainsAsEmptyJSON(t *testing.T) {
+	out := strings.Builder{}
+	operation := NewEvaluateOperation(storage.Store{}, config.Default()).
+		WithoutOutputs().
+		WithExplanation(&out, true)
+
+	err := operation.Run("root", nil)
+
+	require.NoError(t, err)
+	require.Equal(t, "[]\n", out.String())
+}
+
+func TestEvaluateBuildOperation_Explain_NothingSelected(t *testing.T) {
+	out := strings.Builder{}
+	operation := NewEvaluateOperation(storage.Store{}, config.Default()).WithExplanation(&out, false)
+	run := evaluationRun{changes: []info.ChangeInfo{{Status: info.StatusModified, Path: "README.md"}}}
+
+	err := operation.explain(evaluate.Evaluation{}, run)
+
+	require.NoError(t, err)
+	require.Equal(t, "no package is affected by the changes\n", out.String())
+}
@@ -0,0 +1,86 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-lean/bevaluate/evaluate"
+	"github.com/go-lean/bevaluate/info"
+	"io"
+	"path"
+	"strings"
+)
+
+type (
+	gitHubMatrix struct {
+		Include []gitHubMatrixEntry `json:"include"`
+	}
+
+	gitHubMatrixEntry struct {
+		Package     string `json:"package"`
+		Name        string `json:"name"`
+		Environment string `json:"environment,omitempty"`
+		Team        string `json:"team,omitempty"`
+		Image       string `json:"image,omitempty"`
+	}
+)
+
+const (
+	FormatGitHubMatrix = "github-matrix"
+	moduleSeparator    = ":"
+)
+
+func writeGitHubOutputs(out io.Writer, result evaluate.Evaluation) error {
+	outputs := make([]string, 0, 7)
+
+	for _, set := range []struct {
+		name     string
+		packages []string
+	}{
+		{name: "retest", packages: result.Retest},
+		{name: "redeploy", packages: result.Redeploy},
+	} {
+		packages := set.packages
+		if packages == nil {
+			packages = []string{}
+		}
+
+		list, errList := json.Marshal(packages)
+		if errList != nil {
+			return fmt.Errorf("could not marshal %s packages: %w", set.name, errList)
+		}
+
+		matrix, errMatrix := json.Marshal(newGitHubMatrix(packages, result.Deployments))
+		if errMatrix != nil {
+			return fmt.Errorf("could not marshal %s matrix: %w", set.name, errMatrix)
+		}
+
+		outputs = append(outputs,
+			fmt.Sprintf("%s=%s", set.name, list),
+			fmt.Sprintf("%s_matrix=%s", set.name, matrix),
+			fmt.Sprintf("has_%s=%t", set.name, len(packages) > 0),
+		)
+	}
+
+	outputs = append(outputs, fmt.Sprintf("has_changes=%t", len(result.Retest) > 0 || len(result.Redeploy) > 0))
+
+	_, errWrite := io.WriteString(out, strings.Join(outputs, "\n")+"\n")
+	return errWrite
+}
+
+func newGitHubMatrix(packages []string, deployments map[string]info.DeploymentManifest) gitHubMatrix {
+	matrix := gitHubMatrix{Include: make([]gitHubMatrixEntry, 0, len(packages))}
+
+	for _, pkg := range packages {
+		dir := pkg[strings.LastIndex(pkg, moduleSeparator)+1:]
+		manifest := deployments[pkg] // retested packages are not part of the deployments
+		matrix.Include = append(matrix.Include, gitHubMatrixEntry{
+			Package:     pkg,
+			Name:        path.Base(dir),
+			Environment: manifest.Environment,
+			Team:        manifest.Team,
+			Image:       manifest.Image,
+		})
+	}
+
+	return matrix
+}
@@ -0,0 +1,48 @@
+package operations
+
+import (
+	"github.com/go-lean/bevaluate/evaluate"
+	"github.com/go-lean/bevaluate/info"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestWriteGitHubOutputs(t *testing.T) {
+	result := evaluate.Evaluation{
+		Retest:   []string{"keke:move", ".:baba/is"},
+		Redeploy: []string{".:cmd/baba"},
+		Deployments: map[string]info.DeploymentManifest{
+			".:cmd/baba": {Environment: "production", Team: "baba", Image: "baba:latest"},
+		},
+	}
+	out := strings.Builder{}
+
+	err := writeGitHubOutputs(&out, result)
+
+	require.NoError(t, err)
+	require.Equal(t, `retest=["keke:move",".:baba/is"]
+retest_matrix={"include":[{"package":"keke:move","name":"move"},{"package":".:baba/is","name":"is"}]}
+has_retest=true
+redeploy=[".:cmd/baba"]
+redeploy_matrix={"include":[{"package":".:cmd/baba","name":"baba","environment":"production","team":"baba","image":"baba:latest"}]}
+has_redeploy=true
+has_changes=true
+`, out.String())
+}
+
+func TestWriteGitHubOutputs_NothingSelected_EmptyLists(t *testing.T) {
+	out := strings.Builder{}
+
+	err := writeGitHubOutputs(&out, evaluate.Evaluation{})
+
+	require.NoError(t, err)
+	require.Equal(t, `retest=[]
+retest_matrix={"include":[]}
+has_retest=false
+redeploy=[]
+redeploy_matrix={"include":[]}
+has_redeploy=false
+has_changes=false
+`, out.String())
+}
@@ -0,0 +1,153 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-lean/bevaluate/evaluate"
+	"github.com/go-lean/bevaluate/storage"
+	"gopkg.in/yaml.v3"
+	"strings"
+	"text/template"
+)
+
+type (
+	gitLabPipeline struct {
+		retest *template.Template
+		deploy *template.Template
+	}
+
+	gitLabJob struct {
+		Package     string
+		Module      string
+		Dir         string
+		Name        string
+		Environment string
+		Team        string
+		Image       string
+	}
+)
+
+const (
+	gitLabNoOpJob = `bevaluate:no-changes:
+    script:
+        - echo "no affected packages"
+`
+)
+
+var (
+	errNoGitLabTemplates = errors.New("neither a retest nor a deploy template is configured")
+)
+
+func (o EvaluateBuildOperation) WithGitLabPipeline() EvaluateBuildOperation {
+	o.gitLabPipeline = true
+	return o
+}
+
+func (o EvaluateBuildOperation) readGitLabPipeline() (gitLabPipeline, error) {
+	cfg := o.cfg.GitLab
+	if cfg.RetestTemplate == "" && cfg.DeployTemplate == "" {
+		return gitLabPipeline{}, errNoGitLabTemplates
+	}
+
+	pipeline := gitLabPipeline{}
+	for _, item := range []struct {
+		path     string
+		template **template.Template
+	}{
+		{path: cfg.RetestTemplate, template: &pipeline.retest},
+		{path: cfg.DeployTemplate, template: &pipeline.deploy},
+	} {
+		if item.path == "" {
+			continue
+		}
+
+		text, errRead := storage.ReadText(item.path, o.store.FileOpener)
+		if errRead != nil {
+			return gitLabPipeline{}, fmt.Errorf("could not read template %q: %w", item.path, errRead)
+		}
+
+		parsed, errParse := template.New(item.path).Option("missingkey=error").Parse(text)
+		if errParse != nil {
+			return gitLabPipeline{}, fmt.Errorf("could not parse template %q: %w", item.path, errParse)
+		}
+
+		*item.template = parsed
+	}
+
+	return pipeline, nil
+}
+
+func (o EvaluateBuildOperation) writeGitLabPipeline(result evaluate.Evaluation) error {
+	pipeline, errRead := o.readGitLabPipeline()
+	if errRead != nil {
+		return errRead
+	}
+
+	content, errRender := pipeline.render(result, o.cfg.Evaluations.DeploymentsDir)
+	if errRender != nil {
+		return errRender
+	}
+
+	return storage.CreateFileWithText(o.cfg.GitLab.PipelineOut, content, o.store.FileOpener)
+}
+
+func (p gitLabPipeline) render(result evaluate.Evaluation, deploymentsDir string) (string, error) {
+	builder := strings.Builder{}
+
+	if p.retest != nil {
+		for _, pkg := range result.Retest {
+			if errExecute := p.retest.Execute(&builder, newGitLabJob(pkg, "")); errExecute != nil {
+				return "", fmt.Errorf("could not render retest job of %q: %w", pkg, errExecute)
+			}
+
+			builder.WriteString(storage.NewLine)
+		}
+	}
+
+	if p.deploy != nil {
+		for _, pkg := range result.Redeploy {
+			job := newGitLabJob(pkg, deploymentsDir)
+			if manifest, ok := result.Deployments[pkg]; ok {
+				job.Environment, job.Team, job.Image = manifest.Environment, manifest.Team, manifest.Image
+			}
+
+			if errExecute := p.deploy.Execute(&builder, job); errExecute != nil {
+				return "", fmt.Errorf("could not render deploy job of %q: %w", pkg, errExecute)
+			}
+
+			builder.WriteString(storage.NewLine)
+		}
+	}
+
+	content := builder.String()
+	if strings.TrimSpace(content) == "" {
+		return gitLabNoOpJob, nil // gitlab rejects pipelines without jobs
+	}
+
+	jobs := make(map[string]any)
+	if errUnmarshal := yaml.Unmarshal([]byte(content), &jobs); errUnmarshal != nil {
+		return "", fmt.Errorf("rendered pipeline is not valid yaml: %w", errUnmarshal)
+	}
+
+	return content, nil
+}
+
+func newGitLabJob(pkg, deploymentsDir string) gitLabJob {
+	job := gitLabJob{
+		Package: pkg,
+		Module:  rootModuleDir,
+		Dir:     pkg,
+	}
+
+	if i := strings.LastIndex(pkg, moduleSeparator); i >= 0 {
+		job.Module = pkg[:i]
+		job.Dir = pkg[i+1:]
+	}
+
+	job.Name = strings.TrimPrefix(job.Dir, deploymentsDir)
+	if job.Name == "" {
+		job.Name = job.Dir
+	}
+
+	return job
+}
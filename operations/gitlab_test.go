@@ -0,0 +1,144 @@
+package operations
+
+import (
+	"github.com/go-lean/bevaluate/evaluate"
+	"github.com/go-lean/bevaluate/info"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"testing"
+	"text/template"
+)
+
+const (
+	gitLabRetestTemplate = `retest:{{ .Package }}:
+    stage: test
+    script:
+        - go test ./{{ .Dir }}
+`
+	gitLabDeployTemplate = `deploy:{{ .Name }}:
+    stage: deploy
+    environment: {{ .Environment }}
+    script:
+        - make deploy SERVICE={{ .Name }}
+`
+)
+
+func TestGitLabPipeline_Render_NothingSelected_NoOpJob(t *testing.T) {
+	pipeline := newTestGitLabPipeline(gitLabRetestTemplate, gitLabDeployTemplate)
+
+	content, err := pipeline.render(evaluate.Evaluation{}, "cmd/")
+
+	require.NoError(t, err)
+	require.Equal(t, gitLabNoOpJob, content)
+
+	jobs := make(map[string]any)
+	require.NoError(t, yaml.Unmarshal([]byte(content), &jobs))
+	require.Contains(t, jobs, "bevaluate:no-changes")
+}
+
+func TestGitLabPipeline_Render_ValidYAML(t *testing.T) {
+	pipeline := newTestGitLabPipeline(gitLabRetestTemplate, gitLabDeployTemplate)
+	result := evaluate.Evaluation{
+		Retest:   []string{".:baba", "keke:move"},
+		Redeploy: []string{".:cmd/baba", "keke:cmd/keke"},
+		Deployments: map[string]info.DeploymentManifest{
+			".:cmd/baba": {Environment: "production"},
+		},
+	}
+
+	content, err := pipeline.render(result, "cmd/")
+
+	require.NoError(t, err)
+
+	jobs := make(map[string]map[string]any)
+	require.NoError(t, yaml.Unmarshal([]byte(content), &jobs))
+	require.Len(t, jobs, 4)
+	require.Contains(t, jobs, "retest:.:baba")
+	require.Contains(t, jobs, "retest:keke:move")
+	require.Equal(t, "production", jobs["deploy:baba"]["environment"])
+	require.Contains(t, jobs, "deploy:keke")
+}
+
+func TestGitLabPipeline_Render_OnlyDeployTemplate(t *testing.T) {
+	pipeline := newTestGitLabPipeline("", gitLabDeployTemplate)
+	result := evaluate.Evaluation{
+		Retest: []string{"baba"},
+	}
+
+	content, err := pipeline.render(result, "cmd/")
+
+	require.NoError(t, err)
+	require.Equal(t, gitLabNoOpJob, content)
+}
+
+func TestGitLabPipeline_Render_DuplicateJobs_InvalidYAML(t *testing.T) {
+	pipeline := newTestGitLabPipeline("retest:\n    script: [go test]\n", "")
+	result := evaluate.Evaluation{
+		Retest: []string{"baba", "keke"},
+	}
+
+	content, err := pipeline.render(result, "cmd/")
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "yaml")
+	require.Empty(t, content)
+}
+
+func TestNewGitLabJob(t *testing.T) {
+	testCases := []struct {
+		name           string
+		pkg            string
+		deploymentsDir string
+		expected       gitLabJob
+	}{
+		{
+			name:     "single module package should be in the root module",
+			pkg:      "baba/is",
+			expected: gitLabJob{Package: "baba/is", Module: ".", Dir: "baba/is", Name: "baba/is"},
+		},
+		{
+			name:     "qualified package should be split by its module",
+			pkg:      "keke:move/up",
+			expected: gitLabJob{Package: "keke:move/up", Module: "keke", Dir: "move/up", Name: "move/up"},
+		},
+		{
+			name:           "deployment name should be relative to the deployments dir",
+			pkg:            "keke:cmd/baba",
+			deploymentsDir: "cmd/",
+			expected:       gitLabJob{Package: "keke:cmd/baba", Module: "keke", Dir: "cmd/baba", Name: "baba"},
+		},
+		{
+			name:           "deployment outside the deployments dir should keep its dir as name",
+			pkg:            "services/baba",
+			deploymentsDir: "cmd/",
+			expected:       gitLabJob{Package: "services/baba", Module: ".", Dir: "services/baba", Name: "services/baba"},
+		},
+		{
+			name:           "deployment being the deployments dir should keep its dir as name",
+			pkg:            "cmd",
+			deploymentsDir: "cmd",
+			expected:       gitLabJob{Package: "cmd", Module: ".", Dir: "cmd", Name: "cmd"},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			job := newGitLabJob(c.pkg, c.deploymentsDir)
+
+			require.Equal(t, c.expected, job)
+		})
+	}
+}
+
+func newTestGitLabPipeline(retest, deploy string) gitLabPipeline {
+	pipeline := gitLabPipeline{}
+	if retest != "" {
+		pipeline.retest = template.Must(template.New("retest").Option("missingkey=error").Parse(retest))
+	}
+
+	if deploy != "" {
+		pipeline.deploy = template.Must(template.New("deploy").Option("missingkey=error").Parse(deploy))
+	}
+
+	return pipeline
+}
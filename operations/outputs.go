@@ -0,0 +1,173 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-lean/bevaluate/config"
+	"github.com/go-lean/bevaluate/evaluate"
+	"github.com/go-lean/bevaluate/gotest"
+	"github.com/go-lean/bevaluate/info"
+	"github.com/go-lean/bevaluate/storage"
+	"path"
+	"strings"
+	"text/template"
+)
+
+type (
+	outputData struct {
+		Module        string
+		Modules       []info.Module
+		Target        string
+		Retest        []string
+		Redeploy      []string
+		RetestPaths   []string
+		RedeployPaths []string
+		Neutral       []string
+		TestFunctions map[string][]string
+		Deployments   map[string]info.DeploymentManifest
+		RunPatterns   []string
+		Reasons       map[string]evaluate.Reason
+		SpecialCases  []evaluate.Reason
+		FullScale     bool
+	}
+)
+
+const (
+	retestTemplate   = `{{ lines .RetestPaths }}`
+	redeployTemplate = `{{ lines .RedeployPaths }}`
+	neutralTemplate  = `{{ lines .Neutral }}`
+	runTemplate      = `{{ lines .RunPatterns }}`
+)
+
+func (o EvaluateBuildOperation) outputs() []config.Output {
+	cfg := o.cfg.Evaluations
+	outputs := make([]config.Output, 0, len(cfg.Outputs)+3)
+
+	if cfg.RetestOut != "" {
+		outputs = append(outputs, config.Output{Path: cfg.RetestOut, Template: retestTemplate})
+	}
+
+	if cfg.RedeployOut != "" {
+		outputs = append(outputs, config.Output{Path: cfg.RedeployOut, Template: redeployTemplate})
+	}
+
+	if cfg.SkipNeutralChanges && cfg.NeutralOut != "" {
+		outputs = append(outputs, config.Output{Path: cfg.NeutralOut, Template: neutralTemplate})
+	}
+
+	if cfg.SelectTestFunctions && cfg.RunOut != "" {
+		outputs = append(outputs, config.Output{Path: cfg.RunOut, Template: runTemplate})
+	}
+
+	return append(outputs, cfg.Outputs...)
+}
+
+func newOutputData(result evaluate.Evaluation, modules []info.Module, ignored []string, target, pathStyle string) outputData {
+	data := outputData{
+		Modules:       modules,
+		Target:        target,
+		Retest:        result.Retest,
+		Redeploy:      result.Redeploy,
+		RetestPaths:   formatPaths(pathStyle, result.Retest, result.Testable, blockedDirs(ignored, result.Excluded), modules),
+		RedeployPaths: formatPaths(pathStyle, result.Redeploy, result.Deployable, blockedDirs(ignored, undeployable(result)), modules),
+		Neutral:       result.Neutral,
+		TestFunctions: result.TestFunctions,
+		Deployments:   result.Deployments,
+		RunPatterns:   runPatterns(pathStyle, result.Retest, result.TestFunctions, modules),
+		Reasons:       result.Reasons,
+		SpecialCases:  result.SpecialCases,
+		FullScale:     result.FullScale,
+	}
+
+	for _, module := range modules {
+		if module.Dir == rootModuleDir {
+			data.Module = module.Path
+		}
+	}
+
+	return data
+}
+
+// runPatterns returns the -run regex of every retested package which only needs some of its tests,
+// as "<package> <regex>" lines. A pattern style cannot be used for a single package, so it is relative.
+func runPatterns(pathStyle string, retest []string, testFunctions map[string][]string, modules []info.Module) []string {
+	if pathStyle == PathStylePattern {
+		pathStyle = PathStyleRelative
+	}
+
+	result := make([]string, 0, len(testFunctions))
+	for _, pkg := range retest {
+		functions, ok := testFunctions[pkg]
+		if ok == false {
+			continue
+		}
+
+		path := formatPaths(pathStyle, []string{pkg}, nil, nil, modules)[0]
+		result = append(result, path+" "+gotest.RunPattern(functions))
+	}
+
+	return result
+}
+
+func blockedDirs(ignored, packages []string) []string {
+	result := make([]string, 0, len(ignored)+len(packages))
+	return append(append(result, ignored...), packages...)
+}
+
+// undeployable returns the packages which a redeploy pattern must not cover, including the excluded ones.
+func undeployable(result evaluate.Evaluation) []string {
+	deployable := make(map[string]struct{}, len(result.Deployable))
+	for _, pkg := range result.Deployable {
+		deployable[pkg] = struct{}{}
+	}
+
+	packages := make([]string, 0, len(result.Packages))
+	for _, pkg := range result.Packages {
+		if _, ok := deployable[pkg]; ok == false {
+			packages = append(packages, pkg)
+		}
+	}
+
+	return packages
+}
+
+func renderOutput(output config.Output, data outputData) (string, error) {
+	parsed, errParse := template.New(output.Path).
+		Option("missingkey=error").
+		Funcs(outputFuncs(data.Modules)).
+		Parse(output.Template)
+	if errParse != nil {
+		return "", fmt.Errorf("could not parse template: %w", errParse)
+	}
+
+	builder := strings.Builder{}
+	if errExecute := parsed.Execute(&builder, data); errExecute != nil {
+		return "", fmt.Errorf("could not execute template: %w", errExecute)
+	}
+
+	return builder.String(), nil
+}
+
+func outputFuncs(modules []info.Module) template.FuncMap {
+	return template.FuncMap{
+		"lines": func(items []string) string {
+			return strings.Join(items, storage.NewLine)
+		},
+		"join": func(items []string, separator string) string {
+			return strings.Join(items, separator)
+		},
+		"base": path.Base,
+		"dir": func(pkg string) string {
+			return pkg[strings.LastIndex(pkg, moduleSeparator)+1:]
+		},
+		"relative": relativePath,
+		"importPath": func(pkg string) string {
+			return importPath(pkg, modules)
+		},
+		"runPattern": gotest.RunPattern,
+		"json": func(value any) (string, error) {
+			data, errMarshal := json.Marshal(value)
+			return string(data), errMarshal
+		},
+	}
+}
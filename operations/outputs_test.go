@@ -0,0 +1,114 @@
+package operations
+
+import (
+	"github.com/go-lean/bevaluate/config"
+	"github.com/go-lean/bevaluate/evaluate"
+	"github.com/go-lean/bevaluate/info"
+	"github.com/go-lean/bevaluate/storage"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestRenderOutput_DefaultOutputs_JoinedByLines(t *testing.T) {
+	cfg := config.Default()
+	cfg.Evaluations.RetestOut = "bevaluate/retest.out"
+	cfg.Evaluations.RedeployOut = "bevaluate/redeploy.out"
+	operation := NewEvaluateOperation(storage.Store{}, cfg)
+	modules := []info.Module{{Path: "github.com/baba/is", Dir: "."}}
+
+	testCases := []struct {
+		name     string
+		retest   []string
+		redeploy []string
+	}{
+		{
+			name: "nothing selected should be empty",
+		},
+		{
+			name:     "single package should be written as is",
+			retest:   []string{"baba"},
+			redeploy: []string{"cmd/baba"},
+		},
+		{
+			name:     "several packages should be written one per line",
+			retest:   []string{".", "baba/is", "keke"},
+			redeploy: []string{"cmd/baba", "cmd/keke"},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			result := evaluate.Evaluation{Retest: c.retest, Redeploy: c.redeploy}
+			data := newOutputData(result, modules, nil, "", "")
+			expected := map[string]string{
+				"bevaluate/retest.out":   strings.Join(c.retest, storage.NewLine),
+				"bevaluate/redeploy.out": strings.Join(c.redeploy, storage.NewLine),
+			}
+
+			outputs := operation.outputs()
+			require.Len(t, outputs, len(expected))
+
+			for _, output := range outputs {
+				content, err := renderOutput(output, data)
+
+				require.NoError(t, err)
+				require.Equal(t, expected[output.Path], content)
+			}
+		})
+	}
+}
+
+func TestRenderOutput_CustomTemplate(t *testing.T) {
+	modules := []info.Module{
+		{Path: "github.com/baba/is", Dir: "."},
+		{Path: "github.com/baba/keke", Dir: "keke"},
+	}
+	result := evaluate.Evaluation{
+		Retest:   []string{".:baba", "keke:move"},
+		Redeploy: []string{".:cmd/baba"},
+		Deployments: map[string]info.DeploymentManifest{
+			".:cmd/baba": {Team: "flag"},
+		},
+	}
+	data := newOutputData(result, modules, nil, "linux", PathStyleRelative)
+
+	testCases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "fields should be rendered",
+			template: `{{ .Module }} {{ .Target }} {{ join .RetestPaths "," }}`,
+			expected: "github.com/baba/is linux .:./baba,keke:./move",
+		},
+		{
+			name:     "functions should be rendered",
+			template: `{{ range .Retest }}{{ importPath . }} {{ base (dir .) }};{{ end }}`,
+			expected: "github.com/baba/is/baba baba;github.com/baba/keke/move move;",
+		},
+		{
+			name:     "deployments should be rendered as json",
+			template: `{{ json .Deployments }}`,
+			expected: `{".:cmd/baba":{"team":"flag"}}`,
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			content, err := renderOutput(config.Output{Path: "custom.out", Template: c.template}, data)
+
+			require.NoError(t, err)
+			require.Equal(t, c.expected, content)
+		})
+	}
+}
+
+func TestRenderOutput_MissingKey_Error(t *testing.T) {
+	content, err := renderOutput(config.Output{Path: "custom.out", Template: `{{ .Baba }}`}, outputData{})
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "execute")
+	require.Empty(t, content)
+}
@@ -0,0 +1,200 @@
+package operations
+
+import (
+	"fmt"
+	"github.com/go-lean/bevaluate/info"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	PathStyleDir      = "dir"
+	PathStyleRelative = "relative"
+	PathStyleImport   = "import"
+	PathStylePattern  = "pattern"
+
+	relativePrefix = "./"
+	patternSuffix  = "..."
+)
+
+func validatePathStyle(style string) error {
+	switch style {
+	case "", PathStyleDir, PathStyleRelative, PathStyleImport, PathStylePattern:
+		return nil
+	default:
+		return fmt.Errorf("unknown path style: %q", style)
+	}
+}
+
+// formatPaths formats the selected packages in the given style. The pattern style needs the eligible
+// packages, which have to be selected to be covered by a pattern, and the blocked directories, which a
+// pattern must never cover.
+func formatPaths(style string, selected, eligible, blocked []string, modules []info.Module) []string {
+	result := make([]string, 0, len(selected))
+
+	switch style {
+	case PathStyleRelative:
+		for _, pkg := range selected {
+			result = append(result, relativePath(pkg))
+		}
+	case PathStyleImport:
+		for _, pkg := range selected {
+			result = append(result, importPath(pkg, modules))
+		}
+	case PathStylePattern:
+		result = collapsePatterns(selected, eligible, blocked)
+	default:
+		result = append(result, selected...)
+	}
+
+	return result
+}
+
+func relativePath(pkg string) string {
+	moduleDir, dir, qualified := strings.Cut(pkg, moduleSeparator)
+	if qualified == false {
+		dir = moduleDir
+	}
+
+	relative := relativePrefix + dir
+	if dir == rootModuleDir {
+		relative = rootModuleDir
+	}
+
+	if qualified == false {
+		return relative
+	}
+
+	return moduleDir + moduleSeparator + relative
+}
+
+func importPath(pkg string, modules []info.Module) string {
+	dir := pkg
+	if moduleDir, rel, ok := strings.Cut(pkg, moduleSeparator); ok {
+		dir = filepath.Join(moduleDir, rel)
+	}
+
+	module, ok := info.ModuleOf(dir, modules)
+	if ok == false {
+		return pkg
+	}
+
+	return module.ImportPath(dir)
+}
+
+// collapsePatterns replaces the selected packages of a directory by a single ./dir/... pattern, so that
+// e.g. go test receives as few arguments as possible. A directory is only collapsed when it holds more
+// than one selected package, all of its eligible packages are selected and none of it is blocked. The
+// climb towards the root stops as soon as a parent does not hold more selected packages.
+func collapsePatterns(selected, eligible, blocked []string) []string {
+	isSelected := make(map[string]struct{}, len(selected))
+	for _, pkg := range selected {
+		isSelected[pkg] = struct{}{}
+	}
+
+	patterns := make([]string, len(selected))
+	collapsed := make(map[string]struct{})
+
+	for i, pkg := range selected {
+		moduleDir, dir := splitQualified(pkg)
+
+		subtree, covered := dir, 0
+		for candidate := dir; ; candidate = path.Dir(candidate) {
+			count, all := countSubtree(moduleDir, candidate, eligible, blocked, isSelected)
+			if all == false || count <= covered {
+				break
+			}
+
+			subtree, covered = candidate, count
+			if candidate == rootModuleDir {
+				break
+			}
+		}
+
+		pattern := relativePath(dir)
+		if covered > 1 {
+			pattern = relativePrefix + path.Join(subtree, patternSuffix)
+			collapsed[qualify(moduleDir, subtree)] = struct{}{}
+		}
+
+		patterns[i] = qualify(moduleDir, pattern)
+	}
+
+	result := make([]string, 0, len(selected))
+	seen := make(map[string]struct{}, len(selected))
+
+	for i, pkg := range selected {
+		if _, ok := seen[patterns[i]]; ok || isCollapsed(pkg, patterns[i], collapsed) {
+			continue
+		}
+
+		seen[patterns[i]] = struct{}{}
+		result = append(result, patterns[i])
+	}
+
+	return result
+}
+
+// isCollapsed reports whether a package kept on its own is covered by the pattern of another one anyway.
+func isCollapsed(pkg, pattern string, collapsed map[string]struct{}) bool {
+	if strings.HasSuffix(pattern, patternSuffix) {
+		return false
+	}
+
+	moduleDir, dir := splitQualified(pkg)
+	for subtree := range collapsed {
+		subtreeModule, subtreeDir := splitQualified(subtree)
+		if subtreeModule == moduleDir && isInSubtree(dir, subtreeDir) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func countSubtree(moduleDir, dir string, eligible, blocked []string, selected map[string]struct{}) (int, bool) {
+	for _, blockedDir := range blocked {
+		blockedModule, blockedRel := splitQualified(blockedDir)
+		if blockedModule == moduleDir && isInSubtree(blockedRel, dir) {
+			return 0, false
+		}
+	}
+
+	count := 0
+	for _, pkg := range eligible {
+		pkgModule, pkgDir := splitQualified(pkg)
+		if pkgModule != moduleDir || isInSubtree(pkgDir, dir) == false {
+			continue
+		}
+
+		if _, ok := selected[pkg]; ok == false {
+			return 0, false
+		}
+
+		count++
+	}
+
+	return count, true
+}
+
+func splitQualified(pkg string) (string, string) {
+	moduleDir, dir, qualified := strings.Cut(pkg, moduleSeparator)
+	if qualified == false {
+		return "", moduleDir
+	}
+
+	return moduleDir, dir
+}
+
+func qualify(moduleDir, dir string) string {
+	if moduleDir == "" {
+		return dir
+	}
+
+	return moduleDir + moduleSeparator + dir
+}
+
+func isInSubtree(pkgDir, dir string) bool {
+	return dir == rootModuleDir || pkgDir == dir || strings.HasPrefix(pkgDir, dir+"/")
+}
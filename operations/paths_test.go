@@ -0,0 +1,144 @@
+package operations
+
+import (
+	"github.com/go-lean/bevaluate/info"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCollapsePatterns(t *testing.T) {
+	testCases := []struct {
+		name     string
+		selected []string
+		eligible []string
+		blocked  []string
+		expected []string
+	}{
+		{
+			name:     "fully selected subtree should be collapsed",
+			selected: []string{"baba", "baba/is", "baba/is/you", "flag"},
+			eligible: []string{"baba", "baba/is", "baba/is/you", "flag", "keke"},
+			expected: []string{"./baba/...", "./flag"},
+		},
+		{
+			name:     "single selected package should not be collapsed",
+			selected: []string{"baba"},
+			eligible: []string{"baba"},
+			expected: []string{"./baba"},
+		},
+		{
+			name:     "subtree with an ignored dir should not be collapsed",
+			selected: []string{"baba", "baba/is"},
+			eligible: []string{"baba", "baba/is"},
+			blocked:  []string{"baba/is/ignored"},
+			expected: []string{"./baba", "./baba/is"},
+		},
+		{
+			name:     "subtree with a blocked package should only collapse the unblocked part",
+			selected: []string{"baba/is", "baba/is/you", "baba/win"},
+			eligible: []string{"baba/is", "baba/is/you", "baba/win"},
+			blocked:  []string{"baba/excluded"},
+			expected: []string{"./baba/is/...", "./baba/win"},
+		},
+		{
+			name:     "nested partial selection should only collapse the fully selected subtree",
+			selected: []string{"baba/is", "baba/is/you", "baba/is/win", "baba/flag"},
+			eligible: []string{"baba", "baba/is", "baba/is/you", "baba/is/win", "baba/flag"},
+			expected: []string{"./baba/is/...", "./baba/flag"},
+		},
+		{
+			name:     "nested partial selection should keep the packages of a partially selected subtree",
+			selected: []string{"baba/is", "baba/is/you"},
+			eligible: []string{"baba/is", "baba/is/you", "baba/is/win"},
+			expected: []string{"./baba/is", "./baba/is/you"},
+		},
+		{
+			name:     "module qualified paths should be collapsed within their module",
+			selected: []string{"keke:.", "keke:move", "keke:move/up", "baba:is"},
+			eligible: []string{"keke:.", "keke:move", "keke:move/up", "baba:is", "baba:you"},
+			blocked:  []string{"baba:you/ignored"},
+			expected: []string{"keke:./...", "baba:./is"},
+		},
+		{
+			name:     "module qualified blocked dir should not block other modules",
+			selected: []string{"keke:move", "keke:move/up"},
+			eligible: []string{"keke:move", "keke:move/up"},
+			blocked:  []string{"baba:move"},
+			expected: []string{"keke:./move/..."},
+		},
+		{
+			name:     "fully selected root should be collapsed to the root pattern",
+			selected: []string{".", "baba", "baba/is", "flag"},
+			eligible: []string{".", "baba", "baba/is", "flag"},
+			expected: []string{"./..."},
+		},
+		{
+			name:     "blocked root should not be collapsed to the root pattern",
+			selected: []string{".", "baba", "baba/is"},
+			eligible: []string{".", "baba", "baba/is"},
+			blocked:  []string{"vendor"},
+			expected: []string{".", "./baba/..."},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			patterns := collapsePatterns(c.selected, c.eligible, c.blocked)
+
+			require.Equal(t, c.expected, patterns)
+		})
+	}
+}
+
+func TestFormatPaths(t *testing.T) {
+	modules := []info.Module{
+		{Path: "github.com/baba/is", Dir: "."},
+		{Path: "github.com/baba/keke", Dir: "keke"},
+	}
+	selected := []string{".", "baba", "keke:move"}
+
+	testCases := []struct {
+		style    string
+		expected []string
+	}{
+		{
+			style:    "",
+			expected: []string{".", "baba", "keke:move"},
+		},
+		{
+			style:    PathStyleDir,
+			expected: []string{".", "baba", "keke:move"},
+		},
+		{
+			style:    PathStyleRelative,
+			expected: []string{".", "./baba", "keke:./move"},
+		},
+		{
+			style:    PathStyleImport,
+			expected: []string{"github.com/baba/is", "github.com/baba/is/baba", "github.com/baba/keke/move"},
+		},
+		{
+			style:    PathStylePattern,
+			expected: []string{"./...", "keke:./move"},
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.style, func(t *testing.T) {
+			paths := formatPaths(c.style, selected, selected, nil, modules)
+
+			require.Equal(t, c.expected, paths)
+		})
+	}
+}
+
+func TestValidatePathStyle(t *testing.T) {
+	for _, style := range []string{"", PathStyleDir, PathStyleRelative, PathStyleImport, PathStylePattern} {
+		require.NoError(t, validatePathStyle(style))
+	}
+
+	err := validatePathStyle("baba")
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "baba")
+}
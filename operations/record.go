@@ -0,0 +1,39 @@
+package operations
+
+import (
+	"errors"
+	"github.com/go-lean/bevaluate/config"
+	"github.com/go-lean/bevaluate/gotest"
+	"github.com/go-lean/bevaluate/storage"
+)
+
+type (
+	RecordOperation struct {
+		cfg   config.Config
+		store storage.Store
+	}
+)
+
+var (
+	ErrNoHistoryFile = errors.New("no test history file configured")
+)
+
+func NewRecordOperation(store storage.Store, cfg config.Config) RecordOperation {
+	return RecordOperation{
+		cfg:   cfg,
+		store: store,
+	}
+}
+
+func (o RecordOperation) Run(events string) error {
+	if o.cfg.Test.HistoryFile == "" {
+		return ErrNoHistoryFile
+	}
+
+	history, errHistory := readHistory(o.cfg.Test.HistoryFile, o.store.FileOpener)
+	if errHistory != nil {
+		return errHistory
+	}
+
+	return writeHistory(o.cfg.Test.HistoryFile, history.Record(gotest.ParseEvents(events)), o.store.FileOpener)
+}
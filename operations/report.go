@@ -0,0 +1,159 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-lean/bevaluate/evaluate"
+	"github.com/go-lean/bevaluate/gotest"
+	"github.com/go-lean/bevaluate/info"
+	"github.com/go-lean/bevaluate/storage"
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"time"
+)
+
+type (
+	Report struct {
+		SchemaVersion int               `json:"schema_version" yaml:"schema_version"`
+		Module        string            `json:"module,omitempty" yaml:"module,omitempty"`
+		Modules       []ReportModule    `json:"modules" yaml:"modules"`
+		Targets       []string          `json:"targets,omitempty" yaml:"targets,omitempty,flow"`
+		Changes       []ReportChange    `json:"changes" yaml:"changes"`
+		Retest        []ReportPackage   `json:"retest" yaml:"retest"`
+		Redeploy      []ReportPackage   `json:"redeploy" yaml:"redeploy"`
+		Neutral       []string          `json:"neutral" yaml:"neutral"`
+		SpecialCases  []evaluate.Reason `json:"special_cases" yaml:"special_cases"`
+		FullScale     bool              `json:"full_scale" yaml:"full_scale"`
+		Timing        ReportTiming      `json:"timing" yaml:"timing"`
+	}
+
+	ReportModule struct {
+		Path string `json:"path" yaml:"path"`
+		Dir  string `json:"dir" yaml:"dir"`
+	}
+
+	ReportChange struct {
+		Status  string `json:"status" yaml:"status"`
+		Path    string `json:"path" yaml:"path"`
+		OldPath string `json:"old_path,omitempty" yaml:"old_path,omitempty"`
+	}
+
+	ReportPackage struct {
+		Path       string                   `json:"path" yaml:"path"`
+		Reason     *evaluate.Reason         `json:"reason,omitempty" yaml:"reason,omitempty"`
+		Tests      []string                 `json:"tests,omitempty" yaml:"tests,omitempty,flow"`
+		Deployment *info.DeploymentManifest `json:"deployment,omitempty" yaml:"deployment,omitempty"`
+	}
+
+	ReportTiming struct {
+		StartedAt  time.Time `json:"started_at" yaml:"started_at"`
+		DurationMS int64     `json:"duration_ms" yaml:"duration_ms"`
+	}
+
+	evaluationRun struct {
+		root    string
+		started time.Time
+		modules []info.Module
+		changes []info.ChangeInfo
+		ignored []string
+		sizes   map[string]int
+		history gotest.History
+	}
+)
+
+const (
+	ReportSchemaVersion = 1
+	rootModuleDir       = "."
+)
+
+func newReport(run evaluationRun, result evaluate.Evaluation, targets []string) Report {
+	report := Report{
+		SchemaVersion: ReportSchemaVersion,
+		Modules:       make([]ReportModule, 0, len(run.modules)),
+		Targets:       targets,
+		Changes:       make([]ReportChange, 0, len(run.changes)),
+		Retest:        reportPackages(result.Retest, result.Reasons),
+		Redeploy:      reportPackages(result.Redeploy, result.Reasons),
+		Neutral:       result.Neutral,
+		SpecialCases:  result.SpecialCases,
+		FullScale:     result.FullScale,
+		Timing: ReportTiming{
+			StartedAt:  run.started.UTC(),
+			DurationMS: time.Since(run.started).Milliseconds(),
+		},
+	}
+
+	for _, module := range run.modules {
+		if module.Dir == rootModuleDir {
+			report.Module = module.Path
+		}
+
+		report.Modules = append(report.Modules, ReportModule{Path: module.Path, Dir: module.Dir})
+	}
+
+	for _, change := range run.changes {
+		status := ""
+		if change.Status != 0 {
+			status = string(change.Status)
+		}
+
+		report.Changes = append(report.Changes, ReportChange{
+			Status:  status,
+			Path:    change.Path,
+			OldPath: change.OldPath,
+		})
+	}
+
+	if report.Neutral == nil {
+		report.Neutral = []string{}
+	}
+
+	if report.SpecialCases == nil {
+		report.SpecialCases = []evaluate.Reason{}
+	}
+
+	for i := range report.Retest {
+		report.Retest[i].Tests = result.TestFunctions[report.Retest[i].Path]
+	}
+
+	for i := range report.Redeploy {
+		if manifest, ok := result.Deployments[report.Redeploy[i].Path]; ok {
+			report.Redeploy[i].Deployment = &manifest
+		}
+	}
+
+	return report
+}
+
+func reportPackages(paths []string, reasons map[string]evaluate.Reason) []ReportPackage {
+	result := make([]ReportPackage, 0, len(paths))
+
+	for _, path := range paths {
+		pkg := ReportPackage{Path: path}
+		if reason, ok := reasons[path]; ok {
+			pkg.Reason = &reason
+		}
+
+		result = append(result, pkg)
+	}
+
+	return result
+}
+
+func writeReport(path string, report Report, opener storage.FileCreateOpener) error {
+	var data []byte
+	var errMarshal error
+
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		data, errMarshal = yaml.Marshal(report)
+	default:
+		data, errMarshal = json.MarshalIndent(report, "", "  ")
+	}
+
+	if errMarshal != nil {
+		return fmt.Errorf("could not marshal report: %w", errMarshal)
+	}
+
+	return storage.CreateFileWithText(path, string(data), opener)
+}
@@ -0,0 +1,80 @@
+package operations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-lean/bevaluate/evaluate"
+	"github.com/go-lean/bevaluate/gotest"
+	"github.com/go-lean/bevaluate/info"
+	"github.com/go-lean/bevaluate/storage"
+	"io/fs"
+)
+
+func (o EvaluateBuildOperation) WithShard(shard gotest.Shard) EvaluateBuildOperation {
+	o.shard = &shard
+	return o
+}
+
+func (o EvaluateBuildOperation) selectShard(result evaluate.Evaluation, run evaluationRun) evaluate.Evaluation {
+	if o.shard == nil {
+		return result
+	}
+
+	durations := run.history.Durations(run.sizes)
+	weights := make(map[string]float64, len(result.Retest))
+	for _, pkg := range result.Retest {
+		weights[pkg] = durations[importPath(pkg, run.modules)]
+	}
+
+	result.Retest = o.shard.Select(result.Retest, weights)
+	return result
+}
+
+func (o EvaluateBuildOperation) readHistory() (gotest.History, error) {
+	return readHistory(o.cfg.Test.HistoryFile, o.store.FileOpener)
+}
+
+func readHistory(path string, opener storage.FileReadOpener) (gotest.History, error) {
+	if path == "" {
+		return gotest.NewHistory(), nil
+	}
+
+	text, errRead := storage.ReadText(path, opener)
+	if errors.Is(errRead, fs.ErrNotExist) {
+		return gotest.NewHistory(), nil // no tests were recorded yet
+	}
+
+	if errRead != nil {
+		return gotest.History{}, fmt.Errorf("could not read test history: %w", errRead)
+	}
+
+	history := gotest.NewHistory()
+	if errUnmarshal := json.Unmarshal([]byte(text), &history); errUnmarshal != nil {
+		return gotest.History{}, fmt.Errorf("could not unmarshal test history: %w", errUnmarshal)
+	}
+
+	return history, nil
+}
+
+func writeHistory(path string, history gotest.History, opener storage.FileCreateOpener) error {
+	data, errMarshal := json.MarshalIndent(history, "", "  ")
+	if errMarshal != nil {
+		return fmt.Errorf("could not marshal test history: %w", errMarshal)
+	}
+
+	if errWrite := storage.CreateFileWithText(path, string(data), opener); errWrite != nil {
+		return fmt.Errorf("could not write test history: %w", errWrite)
+	}
+
+	return nil
+}
+
+func recordSizes(sizes map[string]int, packages []info.PackageInfo) {
+	for _, pkg := range packages {
+		importPath := pkg.Module.ImportPath(pkg.Path)
+		if len(pkg.Files) > sizes[importPath] {
+			sizes[importPath] = len(pkg.Files)
+		}
+	}
+}
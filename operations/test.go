@@ -0,0 +1,107 @@
+package operations
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-lean/bevaluate/evaluate"
+	"github.com/go-lean/bevaluate/gotest"
+	"github.com/go-lean/bevaluate/storage"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+type (
+	tests struct {
+		tester gotest.Tester
+		out    io.Writer
+	}
+)
+
+func (o EvaluateBuildOperation) WithTests(runner gotest.Runner, out io.Writer) (EvaluateBuildOperation, error) {
+	cfg := o.cfg.Test
+	testerCfg := gotest.Config{
+		Parallelism: cfg.Parallelism,
+		Flags:       cfg.Flags,
+	}
+
+	if testerCfg.Parallelism < 1 {
+		testerCfg.Parallelism = runtime.NumCPU()
+	}
+
+	if cfg.Timeout != "" {
+		timeout, errTimeout := time.ParseDuration(cfg.Timeout)
+		if errTimeout != nil {
+			return o, fmt.Errorf("invalid test timeout: %w", errTimeout)
+		}
+
+		testerCfg.Timeout = timeout
+	}
+
+	o.tests = &tests{
+		tester: gotest.NewTester(runner, testerCfg),
+		out:    out,
+	}
+	return o, nil
+}
+
+func (o EvaluateBuildOperation) runTests(result evaluate.Evaluation, run evaluationRun) error {
+	if o.tests == nil {
+		return nil
+	}
+
+	summary := o.tests.tester.Test(testPackages(result.Retest, result.TestFunctions, run.root))
+	if _, errWrite := io.WriteString(o.tests.out, summary.String()); errWrite != nil {
+		return fmt.Errorf("could not write test summary: %w", errWrite)
+	}
+
+	if o.cfg.Test.ResultsOut != "" {
+		data, errMarshal := json.MarshalIndent(summary, "", "  ")
+		if errMarshal != nil {
+			return fmt.Errorf("could not marshal test results: %w", errMarshal)
+		}
+
+		if errWrite := storage.CreateFileWithText(o.cfg.Test.ResultsOut, string(data), o.store.FileOpener); errWrite != nil {
+			return fmt.Errorf("could not write test results: %w", errWrite)
+		}
+	}
+
+	if o.cfg.Test.HistoryFile != "" {
+		history, errHistory := o.readHistory()
+		if errHistory != nil {
+			return errHistory
+		}
+
+		if errWrite := writeHistory(o.cfg.Test.HistoryFile, history.Record(summary.Packages), o.store.FileOpener); errWrite != nil {
+			return errWrite
+		}
+	}
+
+	if summary.Failed > 0 {
+		return fmt.Errorf("%w: %s", gotest.ErrTestsFailed, strings.Join(summary.FailedPackages(), ", "))
+	}
+
+	return nil
+}
+
+func testPackages(retest []string, testFunctions map[string][]string, root string) []gotest.Package {
+	packages := make([]gotest.Package, 0, len(retest))
+
+	for _, pkg := range retest {
+		moduleDir, dir, qualified := strings.Cut(pkg, moduleSeparator)
+		if qualified == false {
+			moduleDir, dir = rootModuleDir, moduleDir
+		}
+
+		packages = append(packages, gotest.Package{
+			Name:    pkg,
+			Dir:     filepath.Join(root, moduleDir),
+			Pattern: relativePath(dir),
+			Run:     gotest.RunPattern(testFunctions[pkg]),
+		})
+	}
+
+	return packages
+}
@@ -0,0 +1,271 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	GoMod struct {
+		Module     string
+		Deprecated string
+		Go         string
+		Toolchain  string
+		Require    []Requirement
+		Replace    []Replacement
+		Exclude    []ModuleVersion
+	}
+
+	ModuleVersion struct {
+		Path    string
+		Version string
+	}
+
+	Requirement struct {
+		ModuleVersion
+		Indirect bool
+	}
+
+	Replacement struct {
+		Old ModuleVersion
+		New ModuleVersion
+	}
+
+	goModLine struct {
+		number    int
+		fields    []string
+		comment   string
+		commented bool
+	}
+)
+
+const (
+	indirectComment   = "indirect"
+	deprecatedComment = "Deprecated:"
+)
+
+var (
+	errUnterminatedString = errors.New("unterminated string")
+	errUnterminatedBlock  = errors.New("unterminated block")
+)
+
+func ReadGoMod(path string, opener FileReadOpener) (GoMod, error) {
+	data, errRead := readFile(path, opener)
+	if errRead != nil {
+		return GoMod{}, errRead
+	}
+
+	mod, errParse := ParseGoMod(data)
+	if errParse != nil {
+		return GoMod{}, fmt.Errorf("could not parse go.mod: %w", errParse)
+	}
+
+	return mod, nil
+}
+
+func ParseGoMod(data []byte) (GoMod, error) {
+	mod := GoMod{}
+	block := ""
+	comments := make([]string, 0)
+
+	for i, rawLine := range strings.Split(string(data), "\n") {
+		line, errLine := splitGoModLine(i+1, strings.TrimSuffix(rawLine, "\r"))
+		if errLine != nil {
+			return GoMod{}, errLine
+		}
+
+		if len(line.fields) == 0 {
+			if line.commented {
+				comments = append(comments, line.comment)
+			} else {
+				comments = comments[:0]
+			}
+
+			continue
+		}
+
+		if block != "" {
+			if line.fields[0] == ")" {
+				block = ""
+				continue
+			}
+
+			line.fields = append([]string{block}, line.fields...)
+		} else if len(line.fields) == 2 && line.fields[1] == "(" {
+			block = line.fields[0]
+			continue
+		}
+
+		if line.fields[0] == "module" {
+			comments = append(comments, line.comment)
+			mod.Deprecated = deprecation(comments)
+		}
+
+		if errApply := mod.apply(line); errApply != nil {
+			return GoMod{}, errApply
+		}
+
+		comments = comments[:0]
+	}
+
+	if block != "" {
+		return GoMod{}, fmt.Errorf("%w: %s", errUnterminatedBlock, block)
+	}
+
+	return mod, nil
+}
+
+func (m *GoMod) apply(line goModLine) error {
+	args := line.fields[1:]
+
+	switch line.fields[0] {
+	case "module":
+		if len(args) != 1 {
+			return line.invalid()
+		}
+
+		m.Module = args[0]
+	case "go":
+		if len(args) != 1 {
+			return line.invalid()
+		}
+
+		m.Go = args[0]
+	case "toolchain":
+		if len(args) != 1 {
+			return line.invalid()
+		}
+
+		m.Toolchain = args[0]
+	case "require":
+		if len(args) != 2 {
+			return line.invalid()
+		}
+
+		m.Require = append(m.Require, Requirement{
+			ModuleVersion: ModuleVersion{Path: args[0], Version: args[1]},
+			Indirect:      line.comment == indirectComment || strings.HasPrefix(line.comment, indirectComment+";"),
+		})
+	case "exclude":
+		if len(args) != 2 {
+			return line.invalid()
+		}
+
+		m.Exclude = append(m.Exclude, ModuleVersion{Path: args[0], Version: args[1]})
+	case "replace":
+		replacement, ok := parseReplacement(args)
+		if ok == false {
+			return line.invalid()
+		}
+
+		m.Replace = append(m.Replace, replacement)
+	}
+
+	return nil // other directives do not affect the evaluation
+}
+
+func (l goModLine) invalid() error {
+	return fmt.Errorf("invalid %s at line %d", l.fields[0], l.number)
+}
+
+func parseReplacement(args []string) (Replacement, bool) {
+	arrow := -1
+	for i, arg := range args {
+		if arg == "=>" {
+			arrow = i
+			break
+		}
+	}
+
+	if arrow < 1 || arrow > 2 || len(args)-arrow-1 < 1 || len(args)-arrow-1 > 2 {
+		return Replacement{}, false
+	}
+
+	result := Replacement{
+		Old: ModuleVersion{Path: args[0]},
+		New: ModuleVersion{Path: args[arrow+1]},
+	}
+
+	if arrow == 2 {
+		result.Old.Version = args[1]
+	}
+
+	if len(args)-arrow-1 == 2 {
+		result.New.Version = args[arrow+2]
+	}
+
+	return result, true
+}
+
+func splitGoModLine(number int, line string) (goModLine, error) {
+	result := goModLine{number: number}
+
+	for i := 0; i < len(line); {
+		switch c := line[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case strings.HasPrefix(line[i:], "//"):
+			result.comment = strings.TrimSpace(line[i+2:])
+			result.commented = true
+			return result, nil
+		case c == '"' || c == '`':
+			end := i + 1
+			for end < len(line) && line[end] != c {
+				if c == '"' && line[end] == '\\' {
+					end++
+				}
+
+				end++
+			}
+
+			if end >= len(line) {
+				return goModLine{}, fmt.Errorf("%w at line %d", errUnterminatedString, number)
+			}
+
+			value, errUnquote := strconv.Unquote(line[i : end+1])
+			if errUnquote != nil {
+				return goModLine{}, fmt.Errorf("invalid string at line %d: %w", number, errUnquote)
+			}
+
+			result.fields = append(result.fields, value)
+			i = end + 1
+		default:
+			start := i
+			for i < len(line) && line[i] != ' ' && line[i] != '\t' && strings.HasPrefix(line[i:], "//") == false {
+				i++
+			}
+
+			result.fields = append(result.fields, line[start:i])
+		}
+	}
+
+	return result, nil
+}
+
+func deprecation(comments []string) string {
+	paragraph := make([]string, 0, len(comments))
+
+	for _, comment := range comments {
+		if comment == "" {
+			if len(paragraph) > 0 {
+				break
+			}
+
+			continue
+		}
+
+		if len(paragraph) == 0 && strings.HasPrefix(comment, deprecatedComment) == false {
+			continue
+		}
+
+		paragraph = append(paragraph, comment)
+	}
+
+	if len(paragraph) == 0 {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.TrimPrefix(strings.Join(paragraph, " "), deprecatedComment))
+}
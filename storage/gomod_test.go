@@ -0,0 +1,130 @@
+package storage_test
+
+import (
+	"github.com/go-lean/bevaluate/storage"
+	"github.com/go-lean/bevaluate/storage/mocks"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const fullGoMod = `// Deprecated: use github.com/baba/is/win instead.
+// It will not receive updates.
+module "github.com/baba/is/you"
+
+go 1.21
+
+toolchain go1.21.4
+
+require (
+	github.com/stretchr/testify v1.8.4
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	"gopkg.in/yaml.v3" v3.0.1 // indirect; used by testify
+)
+
+require github.com/zyedidia/generic v1.2.1
+
+exclude github.com/zyedidia/generic v1.2.0
+
+replace (
+	github.com/baba/is/flag => ../flag
+	github.com/baba/is/rock v1.0.0 => github.com/baba/is/stone v1.0.1
+)
+
+retract v0.1.0
+`
+
+func TestParseGoMod_OK(t *testing.T) {
+	mod, err := storage.ParseGoMod([]byte(fullGoMod))
+
+	require.NoError(t, err)
+	require.Equal(t, storage.GoMod{
+		Module:     "github.com/baba/is/you",
+		Deprecated: "use github.com/baba/is/win instead. It will not receive updates.",
+		Go:         "1.21",
+		Toolchain:  "go1.21.4",
+		Require: []storage.Requirement{
+			{ModuleVersion: storage.ModuleVersion{Path: "github.com/stretchr/testify", Version: "v1.8.4"}},
+			{ModuleVersion: storage.ModuleVersion{Path: "github.com/davecgh/go-spew", Version: "v1.1.1"}, Indirect: true},
+			{ModuleVersion: storage.ModuleVersion{Path: "gopkg.in/yaml.v3", Version: "v3.0.1"}, Indirect: true},
+			{ModuleVersion: storage.ModuleVersion{Path: "github.com/zyedidia/generic", Version: "v1.2.1"}},
+		},
+		Replace: []storage.Replacement{
+			{
+				Old: storage.ModuleVersion{Path: "github.com/baba/is/flag"},
+				New: storage.ModuleVersion{Path: "../flag"},
+			},
+			{
+				Old: storage.ModuleVersion{Path: "github.com/baba/is/rock", Version: "v1.0.0"},
+				New: storage.ModuleVersion{Path: "github.com/baba/is/stone", Version: "v1.0.1"},
+			},
+		},
+		Exclude: []storage.ModuleVersion{{Path: "github.com/zyedidia/generic", Version: "v1.2.0"}},
+	}, mod)
+}
+
+func TestParseGoMod_LeadingCommentAndCRLF(t *testing.T) {
+	mod, err := storage.ParseGoMod([]byte("// the main module\r\n\r\nmodule github.com/baba/is/you // baba\r\n\r\ngo 1.21\r\n"))
+
+	require.NoError(t, err)
+	require.Equal(t, "github.com/baba/is/you", mod.Module)
+	require.Empty(t, mod.Deprecated)
+	require.Equal(t, "1.21", mod.Go)
+}
+
+func TestParseGoMod_Invalid_Error(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{
+			name:    "module without path should fail",
+			content: "module",
+		},
+		{
+			name:    "require without version should fail",
+			content: "require github.com/baba/is/you",
+		},
+		{
+			name:    "replace without arrow should fail",
+			content: "replace github.com/baba/is/you ../you",
+		},
+		{
+			name:    "replace without target should fail",
+			content: "replace github.com/baba/is/you =>",
+		},
+		{
+			name:    "exclude without version should fail",
+			content: "exclude github.com/baba/is/you",
+		},
+		{
+			name:    "unterminated string should fail",
+			content: `module "github.com/baba/is/you`,
+		},
+		{
+			name:    "unterminated block should fail",
+			content: "require (\n\tgithub.com/baba/is/you v1.0.0\n",
+		},
+	}
+
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := storage.ParseGoMod([]byte(c.content))
+
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestReadGoMod_ParseError(t *testing.T) {
+	reader := NewFakeReadCloser(true, true, strings.NewReader("module"))
+	opener := mocks.NewFileReadOpener(t)
+	opener.On("OpenRead", "baba/go.mod").
+		Return(reader, nil)
+
+	mod, err := storage.ReadGoMod("baba/go.mod", opener)
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "line 1")
+	require.Empty(t, mod.Module)
+}
@@ -21,5 +21,5 @@ func (o FileOpener) OpenCreate(path string) (io.WriteCloser, error) {
 		return nil, fmt.Errorf("could not create directory for new file: %w", errMkDir)
 	}
 
-	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY, os.ModePerm)
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
 }
@@ -1,9 +1,9 @@
 package storage
 
 import (
-	"bytes"
 	"fmt"
 	"io"
+	"strings"
 )
 
 func CreateFileWithText(path, text string, opener FileCreateOpener) error {
@@ -27,9 +27,72 @@ func CreateFileWithText(path, text string, opener FileCreateOpener) error {
 }
 
 func ReadModuleName(path string, opener FileReadOpener) (string, error) {
+	mod, errRead := ReadGoMod(path, opener)
+	if errRead != nil {
+		return "", errRead
+	}
+
+	if mod.Module == "" {
+		return "", fmt.Errorf("missing module directive in: %q", path)
+	}
+
+	return mod.Module, nil
+}
+
+func ReadWorkspaceDirs(path string, opener FileReadOpener) ([]string, error) {
+	data, errRead := readFile(path, opener)
+	if errRead != nil {
+		return nil, errRead
+	}
+
+	dirs := make([]string, 0)
+	inUseBlock := false
+
+	for _, rawLine := range strings.Split(string(data), "\n") {
+		line, _, _ := strings.Cut(rawLine, "//")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if inUseBlock {
+			if fields[0] == ")" {
+				inUseBlock = false
+				continue
+			}
+
+			dirs = append(dirs, strings.Trim(fields[0], "\"`"))
+			continue
+		}
+
+		if fields[0] != "use" || len(fields) < 2 {
+			continue
+		}
+
+		if fields[1] == "(" {
+			inUseBlock = true
+			continue
+		}
+
+		dirs = append(dirs, strings.Trim(fields[1], "\"`"))
+	}
+
+	return dirs, nil
+}
+
+func ReadText(path string, opener FileReadOpener) (string, error) {
+	data, errRead := readFile(path, opener)
+	if errRead != nil {
+		return "", errRead
+	}
+
+	return string(data), nil
+}
+
+func readFile(path string, opener FileReadOpener) ([]byte, error) {
 	file, errOpen := opener.OpenRead(path)
 	if errOpen != nil {
-		return "", fmt.Errorf("could not open file: %w", errOpen)
+		return nil, fmt.Errorf("could not open file: %w", errOpen)
 	}
 
 	defer func() {
@@ -40,13 +103,8 @@ func ReadModuleName(path string, opener FileReadOpener) (string, error) {
 
 	data, errRead := io.ReadAll(file)
 	if errRead != nil {
-		return "", fmt.Errorf("could not read from file: %w", errRead)
-	}
-
-	i := bytes.IndexByte(data, '\n')
-	if i < 0 {
-		return string(data[7:]), nil
+		return nil, fmt.Errorf("could not read from file: %w", errRead)
 	}
 
-	return string(data[7:i]), nil
+	return data, nil
 }
@@ -63,8 +63,103 @@ func TestReadModuleName_NewLine_ShouldOnlyGetName(t *testing.T) {
 	require.Equal(t, "github.com/baba/is/you", name)
 }
 
+func TestReadModuleName_MissingModule_Error(t *testing.T) {
+	r := strings.NewReader("go 1.21\n")
+	reader := NewFakeReadCloser(true, true, r)
+	opener := mocks.NewFileReadOpener(t)
+	opener.On("OpenRead", "baba/go.mod").
+		Return(reader, nil)
+
+	name, err := storage.ReadModuleName("baba/go.mod", opener)
+
+	require.Error(t, err)
+	require.Empty(t, name)
+}
+
+func TestReadModuleName_CommentsAndQuotes_ShouldOnlyGetName(t *testing.T) {
+	r := strings.NewReader("// Deprecated: use baba/is/win\r\nmodule \"github.com/baba/is/you\"\r\n")
+	reader := NewFakeReadCloser(true, true, r)
+	opener := mocks.NewFileReadOpener(t)
+	opener.On("OpenRead", "baba/go.mod").
+		Return(reader, nil)
+
+	name, err := storage.ReadModuleName("baba/go.mod", opener)
+
+	require.NoError(t, err)
+	require.Equal(t, "github.com/baba/is/you", name)
+}
+
 // endregion Read Module Name
 
+// region Read Workspace Dirs
+
+func TestReadWorkspaceDirs_OpenError(t *testing.T) {
+	opener := mocks.NewFileReadOpener(t)
+	opener.On("OpenRead", "baba/go.work").
+		Return(nil, errKaboom)
+
+	dirs, err := storage.ReadWorkspaceDirs("baba/go.work", opener)
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "kaboom")
+	require.Empty(t, dirs)
+}
+
+func TestReadWorkspaceDirs_OK(t *testing.T) {
+	r := strings.NewReader(`go 1.21
+
+// the main module
+use .
+
+use (
+	./services/api // api
+	"./tools"
+)
+
+replace github.com/baba/is/flag => ./flag
+`)
+	reader := NewFakeReadCloser(true, true, r)
+	opener := mocks.NewFileReadOpener(t)
+	opener.On("OpenRead", "baba/go.work").
+		Return(reader, nil)
+
+	dirs, err := storage.ReadWorkspaceDirs("baba/go.work", opener)
+
+	require.NoError(t, err)
+	require.Equal(t, []string{".", "./services/api", "./tools"}, dirs)
+}
+
+// endregion Read Workspace Dirs
+
+// region Read Text
+
+func TestReadText_OpenError(t *testing.T) {
+	opener := mocks.NewFileReadOpener(t)
+	opener.On("OpenRead", "ci/job.tmpl").
+		Return(nil, errKaboom)
+
+	text, err := storage.ReadText("ci/job.tmpl", opener)
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "kaboom")
+	require.Empty(t, text)
+}
+
+func TestReadText_ShouldReadWholeFile(t *testing.T) {
+	r := strings.NewReader("test:{{ .Dir }}:\n    script: go test\n")
+	reader := NewFakeReadCloser(true, true, r)
+	opener := mocks.NewFileReadOpener(t)
+	opener.On("OpenRead", "ci/job.tmpl").
+		Return(reader, nil)
+
+	text, err := storage.ReadText("ci/job.tmpl", opener)
+
+	require.NoError(t, err)
+	require.Equal(t, "test:{{ .Dir }}:\n    script: go test\n", text)
+}
+
+// endregion Read Text
+
 // region Create File With Text
 
 func TestCreateFileWithText_OpenError(t *testing.T) {